@@ -0,0 +1,344 @@
+package hndlor
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrincipalKey defines context data key of authenticated principal
+const PrincipalKey = "principal"
+
+// AuthValidator defines callback to validate credential and return principal;
+// returned [ResponseError] status is kept i.e. 403 for forbidden
+// while other errors respond as server errors
+type AuthValidator func(r *http.Request, credential string) (any, error)
+
+// BasicValidator defines callback to validate basic auth credentials and return principal
+type BasicValidator func(r *http.Request, username, password string) (any, error)
+
+// SecureCompare compares strings in constant time
+func SecureCompare(a, b string) bool {
+	ha := sha256.Sum256([]byte(a))
+	hb := sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// StaticKeys creates [AuthValidator] for fixed credentials mapped to principals
+func StaticKeys(keys map[string]any) AuthValidator {
+	return func(_ *http.Request, credential string) (any, error) {
+		var principal any
+		found := false
+		for key, p := range keys {
+			if SecureCompare(key, credential) && !found {
+				principal = p
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, errUnauthorized()
+		}
+		return principal, nil
+	}
+}
+
+// StaticUsers creates [BasicValidator] for fixed username/password pairs
+// with username as principal
+func StaticUsers(users map[string]string) BasicValidator {
+	return func(_ *http.Request, username, password string) (any, error) {
+		expected, ok := users[username]
+		if !SecureCompare(expected, password) || !ok {
+			return nil, errUnauthorized()
+		}
+		return username, nil
+	}
+}
+
+// errUnauthorized creates default error for failed authentication
+func errUnauthorized() *ResponseError {
+	return Error("unauthorized").
+		Status(http.StatusUnauthorized).
+		Reason("unauthorized")
+}
+
+// authenticate runs validator and saves principal on context data
+func authenticate(w http.ResponseWriter, r *http.Request, next http.Handler, challenge string, validate func() (any, error)) error {
+	principal, err := validate()
+	if err != nil {
+		return authError(w, challenge, err)
+	}
+
+	next.ServeHTTP(w, PatchValue(r, PrincipalKey, principal))
+	return nil
+}
+
+// authError converts error to [ResponseError] with challenge on 401
+func authError(w http.ResponseWriter, challenge string, err error) error {
+	var rErr *ResponseError
+	if !errors.As(err, &rErr) {
+		// internal failures are hidden from client
+		rErr = Error(err.Error()).Server()
+	}
+	if rErr.ResponseStatus() == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", challenge)
+	}
+	return rErr
+}
+
+// BasicAuth middleware builds handler to authenticate using basic auth
+func BasicAuth(realm string, validate BasicValidator) NextHandler {
+	challenge := fmt.Sprintf("Basic realm=%q, charset=\"UTF-8\"", realm)
+
+	return MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		return authenticate(w, r, next, challenge, func() (any, error) {
+			username, password, ok := r.BasicAuth()
+			if !ok {
+				return nil, errUnauthorized()
+			}
+			return validate(r, username, password)
+		})
+	})
+}
+
+// BearerAuth middleware builds handler to authenticate using bearer token
+func BearerAuth(realm string, validate AuthValidator) NextHandler {
+	challenge := fmt.Sprintf("Bearer realm=%q", realm)
+
+	return MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		token, ok := BearerToken(r)
+		if !ok {
+			return authenticate(w, r, next, challenge, func() (any, error) {
+				return nil, errUnauthorized()
+			})
+		}
+
+		return authenticate(w, r, next, challenge+`, error="invalid_token"`, func() (any, error) {
+			return validate(r, token)
+		})
+	})
+}
+
+// BearerToken reads bearer token from Authorization header
+func BearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) < 7 || !strings.EqualFold(auth[:7], "Bearer ") {
+		return "", false
+	}
+
+	token := strings.TrimSpace(auth[7:])
+	return token, len(token) > 0
+}
+
+// APIKeyAuth middleware builds handler to authenticate using api key header
+func APIKeyAuth(header string, validate AuthValidator) NextHandler {
+	challenge := fmt.Sprintf("APIKey header=%q", header)
+
+	return MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		return authenticate(w, r, next, challenge, func() (any, error) {
+			key := r.Header.Get(header)
+			if len(key) < 1 {
+				return nil, errUnauthorized()
+			}
+			return validate(r, key)
+		})
+	})
+}
+
+// SignatureConfig defines options for [VerifySignature] middleware
+type SignatureConfig struct {
+
+	// secrets by key id
+	Keys map[string][]byte
+
+	// header of key id; default X-Key-Id
+	KeyHeader string
+
+	// header of hex encoded signature; default X-Signature
+	Header string
+
+	// header of unix timestamp; default X-Timestamp
+	TimestampHeader string
+
+	// allowed clock difference and replay window; default 5 minutes
+	Window time.Duration
+
+	// maximum body size read for verification; default 1MB
+	MaxBody int64
+}
+
+// withDefaults fills empty options of [SignatureConfig]
+func (c SignatureConfig) withDefaults() SignatureConfig {
+	if len(c.KeyHeader) < 1 {
+		c.KeyHeader = "X-Key-Id"
+	}
+	if len(c.Header) < 1 {
+		c.Header = "X-Signature"
+	}
+	if len(c.TimestampHeader) < 1 {
+		c.TimestampHeader = "X-Timestamp"
+	}
+	if c.Window <= 0 {
+		c.Window = 5 * time.Minute
+	}
+	if c.MaxBody <= 0 {
+		c.MaxBody = 1 << 20
+	}
+	return c
+}
+
+// signaturePayload reads raw body and builds signed payload
+func signaturePayload(r *http.Request, timestamp string, maxBody int64) ([]byte, error) {
+	raw := BodyRaw(r)
+	if raw == nil && r.MultipartForm != nil {
+		return nil, Error("unable to verify signature: multipart body already consumed").
+			Server().
+			Reason("signature_body_consumed")
+	} else if raw == nil && !emptyBody(r) {
+		if r.ContentLength > maxBody {
+			return nil, bodyLimitError(&http.MaxBytesError{Limit: maxBody})
+		}
+
+		var err error
+		raw, err = io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBody))
+		if err != nil {
+			return nil, bodyLimitError(err)
+		}
+		restoreBody(r, raw)
+	}
+
+	// original uri as sub routers strip path prefix
+	uri := r.RequestURI
+	if len(uri) < 1 {
+		uri = r.URL.RequestURI()
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(r.Method)
+	buf.WriteByte('\n')
+	buf.WriteString(uri)
+	buf.WriteByte('\n')
+	buf.WriteString(timestamp)
+	buf.WriteByte('\n')
+	buf.Write(raw)
+
+	return buf.Bytes(), nil
+}
+
+// signPayload creates hex encoded HMAC-SHA256 signature
+func signPayload(secret, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignRequest signs request for [VerifySignature] using key id and secret
+func SignRequest(r *http.Request, keyID string, secret []byte, configs ...SignatureConfig) error {
+	var config SignatureConfig
+	if len(configs) > 0 {
+		config = configs[0]
+	}
+	config = config.withDefaults()
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	payload, err := signaturePayload(r, timestamp, config.MaxBody)
+	if err != nil {
+		return err
+	}
+
+	r.Header.Set(config.KeyHeader, keyID)
+	r.Header.Set(config.TimestampHeader, timestamp)
+	r.Header.Set(config.Header, signPayload(secret, payload))
+	return nil
+}
+
+// seenSignatures defines signatures used within replay window
+type seenSignatures struct {
+	sync.Mutex
+	items map[string]time.Time
+	sweep time.Time
+}
+
+// use records signature and reports false if already used
+func (s *seenSignatures) use(sig string, now time.Time, window time.Duration) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if now.After(s.sweep) {
+		for k, exp := range s.items {
+			if now.After(exp) {
+				delete(s.items, k)
+			}
+		}
+		s.sweep = now.Add(window)
+	}
+
+	if _, ok := s.items[sig]; ok {
+		return false
+	}
+	s.items[sig] = now.Add(2 * window)
+	return true
+}
+
+// VerifySignature middleware builds handler to verify HMAC signature over
+// method, path, timestamp and raw body with key id as principal
+//
+// Use before [PrepareMux] or [PrepareBody] for multipart requests as
+// parsed multipart body keeps no raw copy and fails with server error
+func VerifySignature(config SignatureConfig) NextHandler {
+	config = config.withDefaults()
+	seen := &seenSignatures{items: make(map[string]time.Time)}
+	challenge := fmt.Sprintf("HMAC-SHA256 headers=%q", strings.Join(
+		[]string{config.KeyHeader, config.TimestampHeader, config.Header}, ","),
+	)
+
+	return MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		return authenticate(w, r, next, challenge, func() (any, error) {
+			keyID := r.Header.Get(config.KeyHeader)
+			sig := r.Header.Get(config.Header)
+			timestamp := r.Header.Get(config.TimestampHeader)
+
+			secret, ok := config.Keys[keyID]
+			if !ok || len(sig) < 1 {
+				return nil, errUnauthorized()
+			}
+
+			ts, err := strconv.ParseInt(timestamp, 10, 64)
+			now := time.Now()
+			if err != nil || now.Sub(time.Unix(ts, 0)).Abs() > config.Window {
+				return nil, Error("signature expired").
+					Status(http.StatusUnauthorized).
+					Reason("signature_expired")
+			}
+
+			payload, err := signaturePayload(r, timestamp, config.MaxBody)
+			if err != nil {
+				return nil, err
+			}
+
+			if !hmac.Equal([]byte(sig), []byte(signPayload(secret, payload))) {
+				return nil, Error("invalid signature").
+					Status(http.StatusUnauthorized).
+					Reason("signature_invalid")
+			}
+
+			if !seen.use(sig, now, config.Window) {
+				return nil, Error("signature already used").
+					Status(http.StatusUnauthorized).
+					Reason("signature_replayed")
+			}
+
+			return keyID, nil
+		})
+	})
+}
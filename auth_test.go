@@ -0,0 +1,266 @@
+package hndlor_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func CreateAuthTestRouter() *hndlor.MuxRouter {
+	r := hndlor.Router()
+	whoami := hndlor.New(func(principal string) (hndlor.JSON, error) {
+		return hndlor.JSON{"principal": principal}, nil
+	}, hndlor.Context[string](hndlor.PrincipalKey))
+
+	basic := hndlor.SubRouter("/basic").Use(hndlor.BasicAuth("admin", hndlor.StaticUsers(map[string]string{
+		"admin": "secret",
+	})))
+	basic.Handle("GET /me", whoami)
+	basic.MountTo(r.Mux())
+
+	bearer := hndlor.SubRouter("/bearer").Use(hndlor.BearerAuth("api", func(r *http.Request, token string) (any, error) {
+		if token == "blocked" {
+			return nil, hndlor.Error("account blocked").Status(http.StatusForbidden)
+		}
+		return hndlor.StaticKeys(map[string]any{"t-1": "user-1"})(r, token)
+	}))
+	bearer.Handle("GET /me", whoami)
+	bearer.MountTo(r.Mux())
+
+	keyed := hndlor.SubRouter("/key").Use(hndlor.APIKeyAuth("X-Api-Key", hndlor.StaticKeys(map[string]any{
+		"k-1": "service-1",
+	})))
+	keyed.Handle("GET /me", whoami)
+	keyed.MountTo(r.Mux())
+
+	signed := hndlor.SubRouter("/signed").Use(hndlor.VerifySignature(hndlor.SignatureConfig{
+		Keys: map[string][]byte{"partner": []byte("shared-secret")},
+	}), hndlor.PrepareMux())
+	signed.Handle("POST /me", whoami)
+	signed.MountTo(r.Mux())
+
+	return r
+}
+
+func TestAuthMiddlewares(t *testing.T) {
+	r := CreateAuthTestRouter()
+
+	cases := []struct {
+		path      string
+		setup     func(*http.Request)
+		status    int
+		principal string
+		challenge string
+	}{
+		{"/basic/me", func(r *http.Request) { r.SetBasicAuth("admin", "secret") }, 200, "admin", ""},
+		{"/basic/me", func(r *http.Request) { r.SetBasicAuth("admin", "wrong") }, 401, "", "Basic"},
+		{"/basic/me", func(r *http.Request) {}, 401, "", "Basic"},
+		{"/bearer/me", func(r *http.Request) { r.Header.Set("Authorization", "Bearer t-1") }, 200, "user-1", ""},
+		{"/bearer/me", func(r *http.Request) { r.Header.Set("Authorization", "Bearer t-2") }, 401, "", "invalid_token"},
+		{"/bearer/me", func(r *http.Request) { r.Header.Set("Authorization", "Bearer blocked") }, 403, "", ""},
+		{"/key/me", func(r *http.Request) { r.Header.Set("X-Api-Key", "k-1") }, 200, "service-1", ""},
+		{"/key/me", func(r *http.Request) {}, 401, "", "APIKey"},
+	}
+
+	for _, c := range cases {
+		res, err := RunTestRequest(r, "GET", c.path, c.setup)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := res.Result()
+
+		err = InvalidateTestResultStatus(response, c.status)
+		if err != nil {
+			t.Errorf("%s: %s", c.path, err)
+			continue
+		}
+
+		if !strings.Contains(response.Header.Get("WWW-Authenticate"), c.challenge) ||
+			(len(c.challenge) < 1 && len(response.Header.Get("WWW-Authenticate")) > 0) {
+			t.Errorf("%s: invalid challenge %q", c.path, response.Header.Get("WWW-Authenticate"))
+		}
+
+		if c.status == 200 {
+			var data hndlor.JSON
+			err = RunTestResultDecode(response, &data)
+			if err != nil {
+				t.Error(err)
+			} else if data["principal"] != c.principal {
+				t.Errorf("%s: invalid principal %v", c.path, data)
+			}
+		}
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	r := CreateAuthTestRouter()
+
+	var signed http.Header
+	run := func(secret string, body string, status int, cbs ...func(*http.Request)) {
+		res, err := RunTestRequestBody(r, "POST", "/signed/me", strings.NewReader(body), func(req *http.Request) {
+			req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+			req.RequestURI = req.URL.RequestURI()
+
+			err := hndlor.SignRequest(req, "partner", []byte(secret))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, cb := range cbs {
+				cb(req)
+			}
+			signed = req.Header.Clone()
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = InvalidateTestResultStatus(res.Result(), status)
+		if err != nil {
+			t.Errorf("%s: %s", body, err)
+		}
+	}
+
+	run("shared-secret", `{"amount": 10}`, 200)
+
+	previous := signed
+	run("shared-secret", `{"amount": 10}`, 401, func(req *http.Request) {
+		req.Header = previous
+	})
+
+	run("other-secret", `{"amount": 20}`, 401)
+
+	run("shared-secret", `{"amount": 30}`, 401, func(req *http.Request) {
+		req.Body = io.NopCloser(strings.NewReader(`{"amount": 3000}`))
+	})
+
+	run("shared-secret", `{"amount": 40}`, 401, func(req *http.Request) {
+		req.Header.Set("X-Timestamp", "1000")
+	})
+}
+
+func TestAuthValidatorErrors(t *testing.T) {
+	r := hndlor.Router().Use(hndlor.APIKeyAuth("X-Api-Key", func(r *http.Request, key string) (any, error) {
+		if key == "db" {
+			return nil, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+		}
+		return nil, fmt.Errorf("lookup failed: %w", hndlor.Error("key revoked").Status(http.StatusForbidden))
+	}))
+	r.HandleFunc("GET /me", func(w http.ResponseWriter, r *http.Request) {})
+
+	for key, status := range map[string]int{"db": 500, "revoked": 403} {
+		res, err := RunTestRequest(r, "GET", "/me", func(r *http.Request) {
+			r.Header.Set("X-Api-Key", key)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := res.Result()
+
+		err = InvalidateTestResultStatus(response, status)
+		if err != nil {
+			t.Errorf("%s: %s", key, err)
+		}
+
+		body, _ := io.ReadAll(response.Body)
+		if strings.Contains(string(body), "10.0.0.5") {
+			t.Errorf("%s: internal error exposed: %s", key, body)
+		}
+	}
+}
+
+func TestVerifySignatureBodyLimit(t *testing.T) {
+	r := hndlor.Router().Use(hndlor.VerifySignature(hndlor.SignatureConfig{
+		Keys:    map[string][]byte{"partner": []byte("shared-secret")},
+		MaxBody: 16,
+	}))
+	r.HandleFunc("POST /hook", func(w http.ResponseWriter, r *http.Request) {})
+
+	res, err := RunTestRequestBody(r, "POST", "/hook", strings.NewReader(strings.Repeat("a", 64)), func(req *http.Request) {
+		req.Header.Set("X-Key-Id", "partner")
+		req.Header.Set("X-Signature", "00")
+		req.Header.Set("X-Timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+		req.ContentLength = -1
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InvalidateTestResultStatus(res.Result(), 413)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifySignatureMultipart(t *testing.T) {
+	config := hndlor.SignatureConfig{Keys: map[string][]byte{"partner": []byte("shared-secret")}}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("amount") != "10" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}
+
+	run := func(r http.Handler, status int, reason string) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		_ = mw.WriteField("amount", "10")
+		_ = mw.Close()
+
+		res, err := RunTestRequestBody(r, "POST", "/hook", &body, func(req *http.Request) {
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			req.RequestURI = req.URL.RequestURI()
+
+			err := hndlor.SignRequest(req, "partner", []byte("shared-secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := res.Result()
+
+		err = InvalidateTestResultStatus(response, status)
+		if err != nil {
+			t.Error(err)
+		} else if len(reason) > 0 {
+			var data hndlor.JSON
+			err := RunTestResultDecode(response, &data)
+			if err != nil {
+				t.Fatal(err)
+			} else if data["reason"] != reason {
+				t.Errorf("expected %s reason: %v", reason, data)
+			}
+		}
+	}
+
+	r := hndlor.Router().Use(hndlor.VerifySignature(config), hndlor.PrepareMux())
+	r.HandleFunc("POST /hook", handler)
+	run(r, 200, "")
+
+	r = hndlor.Router().Use(hndlor.PrepareMux(), hndlor.VerifySignature(config))
+	r.HandleFunc("POST /hook", handler)
+	run(r, 500, "signature_body_consumed")
+}
+
+func TestAuthErrorsNotShared(t *testing.T) {
+	validate := hndlor.StaticUsers(map[string]string{"admin": "secret"})
+
+	_, err := validate(nil, "admin", "wrong")
+	if re, ok := err.(*hndlor.ResponseError); ok {
+		re.Status(http.StatusTeapot).Reason("decorated")
+	}
+
+	_, err = validate(nil, "admin", "wrong")
+	if re, ok := err.(*hndlor.ResponseError); !ok || re.ResponseStatus() != http.StatusUnauthorized {
+		t.Errorf("unauthorized error should be fresh: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package hndlor
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// tag name used to define binding source of struct field
+const bindingTag = "hndlor"
+
+// binding sources available on struct tags
+var bindingSources = map[string]ValueSource{
+	"path":    ValueSourcePath,
+	"query":   ValueSourceGet,
+	"get":     ValueSourceGet,
+	"body":    ValueSourceBody,
+	"header":  ValueSourceHeader,
+	"cookie":  ValueSourceCookie,
+	"context": ValueSourceContext,
+	"file":    ValueSourceFile,
+}
+
+// fieldBinding defines how struct field is read from request
+type fieldBinding struct {
+	index  []int
+	name   string
+	source ValueSource
+	tagged bool
+	rType  reflect.Type
+	rules  []Rule
+	nested bool
+}
+
+// bindingPlan defines cached bindings of struct fields
+type bindingPlan struct {
+	fields    []fieldBinding
+	tagged    bool
+	validated bool
+}
+
+// cached [bindingPlan] by [reflect.Type]
+var bindingPlans sync.Map
+
+// bindingPlanFor builds or retrieves cached [bindingPlan] for struct
+// and panics on invalid validate tags
+//
+// Fields can define source and name via tags like `hndlor:"path=id"`,
+// `hndlor:"query=q"`, `hndlor:"header=X-Token"`, `hndlor:"body=name"`
+// or `hndlor:"cookie=sid"`; `hndlor:"name"` only renames the field
+// and `hndlor:"-"` skips the field; untagged fields use json tag name
+// or lowercase field name
+func bindingPlanFor(tp reflect.Type) *bindingPlan {
+	return buildBindingPlan(tp, make(map[reflect.Type]bool))
+}
+
+// buildBindingPlan builds [bindingPlan] while tracking
+// visiting types to avoid recursive struct definitions
+func buildBindingPlan(tp reflect.Type, visiting map[reflect.Type]bool) *bindingPlan {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if cached, ok := bindingPlans.Load(tp); ok {
+		return cached.(*bindingPlan)
+	}
+	visiting[tp] = true
+
+	plan := &bindingPlan{
+		fields: make([]fieldBinding, 0),
+	}
+
+	if tp.Kind() == reflect.Struct {
+		for i := range tp.NumField() {
+			f := tp.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			fb := fieldBinding{
+				index:  f.Index,
+				name:   strings.ToLower(f.Name),
+				source: ValueSourceDefault,
+				rType:  f.Type,
+			}
+
+			if jName, _, _ := strings.Cut(f.Tag.Get("json"), ","); len(jName) > 0 && jName != "-" {
+				fb.name = jName
+			}
+
+			tag, hasTag := f.Tag.Lookup(bindingTag)
+			if tag == "-" {
+				continue
+			} else if hasTag && len(tag) > 0 {
+				src, name, hasName := strings.Cut(tag, "=")
+				source, ok := bindingSources[strings.ToLower(strings.TrimSpace(src))]
+				if ok {
+					fb.source = source
+					fb.tagged = true
+					plan.tagged = true
+				} else if !hasName {
+					name = src
+				}
+				if name = strings.TrimSpace(name); len(name) > 0 {
+					fb.name = name
+				}
+			}
+
+			if vTag, ok := f.Tag.Lookup(validationTag); ok {
+				rules, err := parseRules(vTag)
+				if err != nil {
+					panic(Errorf("invalid validate tag on %s.%s: %s", tp, f.Name, err).Server())
+				}
+				fb.rules = rules
+			}
+
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && !visiting[ft] && !isScalar(ft) {
+				fb.nested = buildBindingPlan(ft, visiting).validated
+			}
+			plan.validated = plan.validated || len(fb.rules) > 0 || fb.nested
+
+			plan.fields = append(plan.fields, fb)
+		}
+	}
+
+	cached, _ := bindingPlans.LoadOrStore(tp, plan)
+	return cached.(*bindingPlan)
+}
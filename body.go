@@ -1,119 +1,266 @@
-package hndlor
-
-import (
-	"encoding/json"
-	"errors"
-	"net/http"
-	"reflect"
-	"slices"
-	"strings"
-)
-
-// ContentType of json
-const ContentTypeJSON = "application/json"
-
-// ContentType of url encoded form
-const ContentTypeURLEncoded = "application/x-www-form-urlencoded"
-
-// ContentType of multipart
-const ContentTypeMultipart = "multipart/form-data"
-
-// HasBody checks if request has body
-func HasBody(r *http.Request) bool {
-	return slices.Contains([]string{"POST", "PUT", "PATCH"}, r.Method)
-}
-
-// PrepareBody parses any body request
-func PrepareBody(r *http.Request) (*http.Request, error) {
-	if HasBody(r) {
-		cType := r.Header.Get("Content-Type")
-
-		switch {
-		case strings.Contains(cType, ContentTypeMultipart):
-			err := r.ParseMultipartForm(0)
-			if err != nil {
-				return nil, err
-			}
-
-		case cType == ContentTypeURLEncoded:
-			err := r.ParseForm()
-			if err != nil {
-				return nil, err
-			}
-
-		case cType == ContentTypeJSON, cType == "":
-			eJSON := BodyJSON(r)
-			if eJSON != nil {
-				return r, nil
-			}
-
-			var data JSON
-			err := json.NewDecoder(r.Body).Decode(&data)
-			if err != nil {
-				return nil, err
-			}
-			return Patch(r, ContextValueJSON, data), nil
-		}
-	}
-
-	return r, nil
-}
-
-// BodyJSON reads the loaded json data from request context
-func BodyJSON(r *http.Request) JSON {
-	raw := r.Context().Value(ContextValueJSON)
-	if raw != nil {
-		return raw.(JSON)
-	}
-	return nil
-}
-
-// BodyRead reads value from request body
-func BodyRead(r *http.Request, key string) (any, bool) {
-	if r.Form != nil && r.Form.Has(key) {
-		return r.FormValue(key), true
-	} else if r.PostForm != nil && r.PostForm.Has(key) {
-		return r.PostFormValue(key), true
-	}
-
-	data := BodyJSON(r)
-	if data != nil {
-		v, ok := data[key]
-		return v, ok
-	}
-
-	return "", false
-}
-
-// BodyReadStruct reads values from request body as struct
-func BodyReadStruct[T any](r *http.Request, data T) error {
-	err := errors.New("failed to decode body")
-
-	if HasBody(r) {
-		cType := r.Header.Get("Content-Type")
-		if cType == ContentTypeJSON {
-			jData := BodyJSON(r)
-			if jData == nil {
-				return err
-			}
-
-			return StructToStruct(jData, data)
-		}
-
-		fields := ReadFields(reflect.TypeOf(data))
-		values := make(map[string]any)
-		for _, key := range fields {
-			if r.Form != nil && r.Form.Has(key) {
-				values[key] = r.FormValue(key)
-			} else if r.PostForm != nil && r.PostForm.Has(key) {
-				values[key] = r.PostFormValue(key)
-			}
-		}
-
-		if len(values) > 0 {
-			return StructToStruct(values, data)
-		}
-	}
-
-	return err
-}
+package hndlor
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ContentType of json
+const ContentTypeJSON = "application/json"
+
+// ContentType of url encoded form
+const ContentTypeURLEncoded = "application/x-www-form-urlencoded"
+
+// ContentType of multipart
+const ContentTypeMultipart = "multipart/form-data"
+
+// ContentType of xml
+const ContentTypeXML = "application/xml"
+
+// ContentType of plain text
+const ContentTypeText = "text/plain"
+
+// HasBody checks if request has body
+func HasBody(r *http.Request) bool {
+	return slices.Contains([]string{"POST", "PUT", "PATCH"}, r.Method)
+}
+
+// PrepareBody parses any body request using [BodyDecoder]
+// registered for its Content-Type within optional [BodyConfig] limits
+//
+// Raw bytes of decoded non-multipart body, or any body when MaxSize is set,
+// are cached and request body is restored so it can be read again by handlers
+func PrepareBody(r *http.Request, configs ...*BodyConfig) (*http.Request, error) {
+	if HasBody(r) {
+		if r.Context().Value(ContextValueBodyConfig) != nil {
+			return r, nil
+		}
+
+		orig := r
+		config := NewBodyConfig()
+		if len(configs) > 0 {
+			config = configs[0].For(r)
+		}
+
+		if config.MaxSize > 0 && !emptyBody(r) {
+			if r.ContentLength > config.MaxSize {
+				return nil, bodyLimitError(&http.MaxBytesError{Limit: config.MaxSize})
+			}
+			r.Body = http.MaxBytesReader(nil, r.Body, config.MaxSize)
+		}
+
+		// bodies without decoder are left streaming unless size is limited
+		dec, hasDecoder := BodyDecoderFor(r)
+
+		var raw []byte
+		if (hasDecoder || config.MaxSize > 0) && MediaType(r) != ContentTypeMultipart && !emptyBody(r) {
+			var err error
+			raw, err = io.ReadAll(r.Body)
+			if err != nil {
+				return nil, bodyLimitError(err)
+			}
+			restoreBody(r, raw)
+		}
+
+		r = Patch(r, ContextValueBodyConfig, config)
+		if raw != nil {
+			r = Patch(r, ContextValueRaw, raw)
+		}
+
+		if !hasDecoder {
+			return r, nil
+		}
+
+		data, err := dec(r)
+		if err != nil {
+			return nil, bodyLimitError(err)
+		}
+
+		// net/http only removes temp files of the original request
+		if r.MultipartForm != nil {
+			orig.MultipartForm = r.MultipartForm
+		}
+
+		if raw != nil {
+			restoreBody(r, raw)
+		}
+
+		if data != nil {
+			r = Patch(r, ContextValueBody, data)
+			if jData, ok := asJSON(data); ok {
+				return Patch(r, ContextValueJSON, jData), nil
+			}
+		}
+	}
+
+	return r, nil
+}
+
+// restoreBody replaces request body with cached raw bytes
+func restoreBody(r *http.Request, raw []byte) {
+	r.Body = io.NopCloser(bytes.NewReader(raw))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(raw)), nil
+	}
+}
+
+// asJSON converts map data to [JSON]
+func asJSON(data any) (JSON, bool) {
+	switch v := data.(type) {
+	case JSON:
+		return v, true
+	case map[string]any:
+		return JSON(v), true
+	}
+	return nil, false
+}
+
+// BodyRaw reads the cached raw body bytes from request context
+func BodyRaw(r *http.Request) []byte {
+	raw := r.Context().Value(ContextValueRaw)
+	if raw != nil {
+		return raw.([]byte)
+	}
+	return nil
+}
+
+// BodyData reads the decoded body data of any shape from request context
+func BodyData(r *http.Request) any {
+	return r.Context().Value(ContextValueBody)
+}
+
+// BodyJSON reads the loaded json data from request context
+func BodyJSON(r *http.Request) JSON {
+	raw := r.Context().Value(ContextValueJSON)
+	if raw != nil {
+		return raw.(JSON)
+	}
+	return nil
+}
+
+// BodyRead reads value from request body
+func BodyRead(r *http.Request, key string) (any, bool) {
+	if r.Form != nil && r.Form.Has(key) {
+		return r.FormValue(key), true
+	} else if r.PostForm != nil && r.PostForm.Has(key) {
+		return r.PostFormValue(key), true
+	}
+
+	data := BodyJSON(r)
+	if data != nil {
+		v, ok := data[key]
+		if !ok {
+			v, ok = LookupPath(data, key)
+		}
+		return v, ok
+	}
+
+	return "", false
+}
+
+// LookupPath reads nested value from data using dotted path like
+// "address.city" or JSON Pointer like "/items/0/qty"
+func LookupPath(data any, path string) (any, bool) {
+	var parts []string
+	if strings.HasPrefix(path, "/") {
+		parts = strings.Split(path[1:], "/")
+		for i, part := range parts {
+			parts[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(part)
+		}
+	} else if strings.Contains(path, ".") {
+		parts = strings.Split(path, ".")
+	} else {
+		return nil, false
+	}
+
+	current := data
+	for _, part := range parts {
+		switch node := current.(type) {
+		case JSON:
+			v, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case map[string]any:
+			v, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []any:
+			idx, err := strconv.Atoi(part)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return nil, false
+			}
+			current = node[idx]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// BodyReadStruct reads values from request body as struct
+func BodyReadStruct[T any](r *http.Request, data T) error {
+	err := errors.New("failed to decode body")
+
+	if HasBody(r) {
+		ok, err := decodeBodyJSON(r, data, false)
+		if ok {
+			return err
+		}
+
+		jData := BodyJSON(r)
+		if jData != nil {
+			return StructToStruct(jData, data)
+		}
+
+		fields := ReadFields(reflect.TypeOf(data))
+		values := make(map[string]any)
+		for _, key := range fields {
+			if r.Form != nil && r.Form.Has(key) {
+				values[key] = r.FormValue(key)
+			} else if r.PostForm != nil && r.PostForm.Has(key) {
+				values[key] = r.PostFormValue(key)
+			}
+		}
+
+		if len(values) > 0 {
+			return StructToStruct(values, data)
+		}
+	}
+
+	return err
+}
+
+// decodeBodyJSON decodes cached raw json body directly into data
+// and rejects unknown fields when strict
+func decodeBodyJSON(r *http.Request, data any, strict bool) (bool, error) {
+	raw := BodyRaw(r)
+	if raw == nil || !isJSONBody(r) {
+		return false, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+
+	err := dec.Decode(data)
+	if err != nil {
+		return true, Errorf("invalid body: %s", err.Error()).
+			Status(http.StatusBadRequest).
+			Reason("body_invalid")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,89 @@
+package hndlor
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+)
+
+// BodyConfig defines limits for parsing request body
+type BodyConfig struct {
+
+	// maximum size of body in bytes; 0 for unlimited
+	MaxSize int64
+
+	// maximum bytes of multipart files stored in memory
+	MaxMemory int64
+
+	// maximum number of multipart files; 0 for unlimited
+	MaxFiles int
+
+	// route specific overrides
+	routes *http.ServeMux
+}
+
+// bodyRoute holds [BodyConfig] override for route pattern
+type bodyRoute struct {
+	config *BodyConfig
+}
+
+func (bodyRoute) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+// Route overrides config for requests matching the
+// [http.ServeMux] pattern and panics on invalid pattern
+func (c *BodyConfig) Route(pattern string, config *BodyConfig) *BodyConfig {
+	if c.routes == nil {
+		c.routes = http.NewServeMux()
+	}
+
+	c.routes.Handle(pattern, bodyRoute{config})
+	return c
+}
+
+// For resolves the config for request
+func (c *BodyConfig) For(r *http.Request) *BodyConfig {
+	if c.routes != nil {
+		h, _ := c.routes.Handler(r)
+		if br, ok := h.(bodyRoute); ok {
+			return br.config
+		}
+	}
+	return c
+}
+
+// tooManyFiles creates error for exceeded multipart file count
+func (c *BodyConfig) tooManyFiles() error {
+	return Errorf("too many files; maximum allowed is %d", c.MaxFiles).
+		Status(http.StatusRequestEntityTooLarge).
+		Reason("body_too_large")
+}
+
+// NewBodyConfig creates body config instance without limits
+func NewBodyConfig() *BodyConfig {
+	return &BodyConfig{}
+}
+
+// GetBodyConfig retrieves [BodyConfig] used to parse the request body
+func GetBodyConfig(r *http.Request) *BodyConfig {
+	raw := r.Context().Value(ContextValueBodyConfig)
+	if raw != nil {
+		return raw.(*BodyConfig)
+	}
+	return NewBodyConfig()
+}
+
+// bodyLimitError converts body size errors into [ResponseError]
+func bodyLimitError(err error) error {
+	var mErr *http.MaxBytesError
+	if errors.As(err, &mErr) {
+		return Errorf("request body too large; maximum allowed is %d bytes", mErr.Limit).
+			Status(http.StatusRequestEntityTooLarge).
+			Reason("body_too_large")
+	} else if errors.Is(err, multipart.ErrMessageTooLarge) {
+		return Error("request body too large").
+			Status(http.StatusRequestEntityTooLarge).
+			Reason("body_too_large")
+	}
+
+	return err
+}
@@ -0,0 +1,358 @@
+package hndlor_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func TestBodyDecoders(t *testing.T) {
+	cases := map[string]string{
+		"application/json; charset=utf-8":   `{"username":"admin","password":"pass"}`,
+		"application/vnd.partner+json":      `{"username":"admin","password":"pass"}`,
+		"application/xml":                   `<login type="basic"><username>admin</username><password>pass</password></login>`,
+		"application/x-www-form-urlencoded": "username=admin&password=pass",
+	}
+
+	for cType, body := range cases {
+		req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", cType)
+
+		req, err = hndlor.PrepareBody(req)
+		if err != nil {
+			t.Errorf("unable to prepare body for [%s]: %s", cType, err)
+			continue
+		}
+
+		var creds TestLoginCredentials
+		err = hndlor.BodyReadStruct(req, &creds)
+		if err != nil || creds.Username != "admin" || creds.Password != "pass" {
+			t.Errorf("unable to read body for [%s]", cType)
+		}
+	}
+}
+
+func TestRegisterBodyDecoder(t *testing.T) {
+	hndlor.RegisterBodyDecoder("text/csv", func(r *http.Request) (any, error) {
+		var buf bytes.Buffer
+		_, err := buf.ReadFrom(r.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		parts := strings.Split(strings.TrimSpace(buf.String()), ",")
+		return hndlor.JSON{"username": parts[0]}, nil
+	})
+
+	req, err := http.NewRequest("POST", "/", strings.NewReader("admin,pass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "text/csv")
+
+	req, err = hndlor.PrepareBody(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	username, ok := hndlor.BodyRead(req, "username")
+	if !ok || username != "admin" {
+		t.Error("unable to read body from custom decoder")
+	}
+}
+
+func TestBodyLimits(t *testing.T) {
+	config := &hndlor.BodyConfig{MaxSize: 16}
+	config.Route("POST /upload", &hndlor.BodyConfig{MaxSize: 1024})
+
+	r := hndlor.Router().Use(hndlor.PrepareMux(config))
+	for _, pattern := range []string{"POST /login", "POST /upload"} {
+		r.Handle(pattern, hndlor.New(func(username string) (hndlor.JSON, error) {
+			return hndlor.JSON{"username": username}, nil
+		}, hndlor.Body[string]("username")))
+	}
+
+	data := TestLoginCredentials{Username: "administrator", Password: "password"}
+
+	res, err := RunTestJSONRequest(r, "POST", "/login", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 413)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["reason"] != "body_too_large" {
+			t.Error("unable to resolve body too large error")
+		}
+	}
+
+	res, err = RunTestJSONRequest(r, "POST", "/upload", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InvalidateTestResultStatus(res.Result(), 200)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+type TestItem struct {
+	SKU string
+	Qty int
+}
+
+type TestShipping struct {
+	City  string
+	Zip   int `json:"postal_code"`
+	Items []TestItem
+}
+
+func TestNestedBodyValues(t *testing.T) {
+	body := `{
+		"address": {"city": "Pokhara", "postal_code": "33700"},
+		"items": [{"sku": "A-1", "qty": "3"}, {"sku": "B-2", "qty": 5}],
+		"meta": {"a/b": {"c~d": true}}
+	}`
+
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+
+	req, err = hndlor.PrepareBody(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Body[string]("address.city").As("city"),
+		hndlor.Body[int]("/items/0/qty").As("qty"),
+		hndlor.Body[bool]("/meta/a~1b/c~0d").As("flag"),
+		hndlor.Body[TestShipping]("address").As("shipping"),
+		hndlor.Body[[]TestItem]("items"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shipping := values["shipping"].(TestShipping)
+	items := values["items"].([]TestItem)
+	if values["city"] != "Pokhara" || values["qty"] != 3 || values["flag"] != true ||
+		shipping.City != "Pokhara" || shipping.Zip != 33700 ||
+		len(items) != 2 || items[0].Qty != 3 || items[1].SKU != "B-2" {
+		t.Errorf("unable to resolve nested body values: %v", values)
+	}
+}
+
+func TestRawBodyPayloads(t *testing.T) {
+	req, err := http.NewRequest("POST", "/", strings.NewReader(`[{"sku": "A-1", "qty": 2}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+
+	req, err = hndlor.PrepareBody(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := hndlor.Values(nil, req, hndlor.Struct[[]TestItem]().As("items"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := values["items"].([]TestItem)
+	if len(items) != 1 || items[0].SKU != "A-1" || items[0].Qty != 2 {
+		t.Errorf("unable to resolve array body: %v", items)
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != string(hndlor.BodyRaw(req)) || len(raw) < 1 {
+		t.Errorf("request body was not restored: %q", raw)
+	}
+
+	req, err = http.NewRequest("POST", "/", strings.NewReader(`42`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+
+	req, err = hndlor.PrepareBody(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err = hndlor.Values(nil, req, hndlor.Body[int]("").As("num"))
+	if err != nil || values["num"] != 42 {
+		t.Errorf("unable to resolve scalar body: %v, %v", values, err)
+	}
+}
+
+type TestAccount struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestStrictBodyBinding(t *testing.T) {
+	prepare := func(body string) *http.Request {
+		req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+
+		req, err = hndlor.PrepareBody(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	req := prepare(`{"id": 9007199254740993, "name": "admin"}`)
+	if _, ok := hndlor.BodyJSON(req)["id"].(json.Number); !ok {
+		t.Errorf("expected json.Number in cached body: %v", hndlor.BodyJSON(req))
+	}
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Struct[TestAccount]().As("account"),
+		hndlor.Body[int64]("id"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values["account"].(TestAccount).ID != 9007199254740993 || values["id"] != int64(9007199254740993) {
+		t.Errorf("large integer lost precision: %v", values)
+	}
+
+	req = prepare(`{"id": 1, "name": "admin", "role": "root"}`)
+	_, err = hndlor.Values(nil, req, hndlor.Struct[TestAccount]().Strict().As("account"))
+	if rErr, ok := err.(*hndlor.ResponseError); !ok || rErr.ResponseStatus() != http.StatusBadRequest {
+		t.Errorf("expected strict binding to fail with 400: %v", err)
+	}
+
+	_, err = hndlor.Values(nil, req, hndlor.Struct[TestAccount]().As("account"))
+	if err != nil {
+		t.Errorf("expected unknown fields to be accepted: %v", err)
+	}
+}
+
+func TestUndecodedBodyStreaming(t *testing.T) {
+	payload := bytes.Repeat([]byte{0x01}, 1<<20)
+
+	for _, config := range []*hndlor.BodyConfig{hndlor.NewBodyConfig(), {MaxSize: 2 << 20}} {
+		var buffered bool
+		var size int
+
+		r := hndlor.Router().Use(hndlor.PrepareMux(config))
+		r.HandleFunc("POST /blob", func(w http.ResponseWriter, r *http.Request) {
+			buffered = hndlor.BodyRaw(r) != nil
+			data, _ := io.ReadAll(r.Body)
+			size = len(data)
+		})
+
+		_, err := RunTestRequestBody(r, "POST", "/blob", bytes.NewReader(payload), func(r *http.Request) {
+			r.Header.Set("Content-Type", "application/octet-stream")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if buffered != (config.MaxSize > 0) || size != len(payload) {
+			t.Errorf("invalid body buffering with max size %d: buffered %v, size %d", config.MaxSize, buffered, size)
+		}
+	}
+}
+
+type TestTaggedAccount struct {
+	Tenant string `hndlor:"header=X-Tenant"`
+	Name   string `hndlor:"body=name"`
+	City   string `hndlor:"body=address.city"`
+}
+
+func TestStrictTaggedBinding(t *testing.T) {
+	prepare := func(body string) *http.Request {
+		req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+		req.Header.Set("X-Tenant", "main")
+
+		req, err = hndlor.PrepareBody(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	values, err := hndlor.Values(nil, prepare(`{"name": "admin", "address": {"city": "Pokhara"}}`),
+		hndlor.Struct[TestTaggedAccount]().Strict().As("account"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	} else if account := values["account"].(TestTaggedAccount); account.Tenant != "main" || account.City != "Pokhara" {
+		t.Errorf("unable to bind tagged struct: %v", account)
+	}
+
+	req := prepare(`{"name": "admin", "role": "root"}`)
+	_, err = hndlor.Values(nil, req, hndlor.Struct[TestTaggedAccount]().Strict().As("account"))
+	if rErr, ok := err.(*hndlor.ResponseError); !ok || rErr.ResponseStatus() != http.StatusBadRequest {
+		t.Errorf("expected strict tagged binding to fail with 400: %v", err)
+	}
+
+	_, err = hndlor.Values(nil, req, hndlor.Struct[TestTaggedAccount]().As("account"))
+	if err != nil {
+		t.Errorf("expected unknown fields to be accepted: %v", err)
+	}
+}
+
+func TestXMLElementText(t *testing.T) {
+	body := `<payment id="7"><amount currency="USD">10</amount><note lang="en"> </note></payment>`
+
+	req, err := http.NewRequest("POST", "/", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", hndlor.ContentTypeXML)
+
+	req, err = hndlor.PrepareBody(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Body[string]("id"),
+		hndlor.Body[int]("amount."+hndlor.XMLTextKey).As("amount"),
+		hndlor.Body[string]("amount.currency").As("currency"),
+		hndlor.Body[hndlor.JSON]("note"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	note := values["note"].(hndlor.JSON)
+	if values["id"] != "7" || values["amount"] != 10 || values["currency"] != "USD" ||
+		note["lang"] != "en" || note[hndlor.XMLTextKey] != nil {
+		t.Errorf("unable to decode xml element text: %v", values)
+	}
+}
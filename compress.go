@@ -0,0 +1,275 @@
+package hndlor
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DefaultCompressSkipTypes defines media types already compressed
+var DefaultCompressSkipTypes = []string{
+	"image/*",
+	"video/*",
+	"audio/*",
+	"font/woff2",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zstd",
+}
+
+// CompressConfig defines options for [Compress] middleware
+type CompressConfig struct {
+
+	// minimum body size to compress; default 1024
+	MinSize int
+
+	// compression level from [gzip.HuffmanOnly] to [gzip.BestCompression];
+	// default [gzip.DefaultCompression]
+	Level int
+
+	// frame responses without compressing them, overriding Level
+	NoCompression bool
+
+	// media types to skip supporting type/* wildcard;
+	// default [DefaultCompressSkipTypes]
+	SkipTypes []string
+}
+
+// NewCompressConfig creates default [CompressConfig]
+func NewCompressConfig() *CompressConfig {
+	return &CompressConfig{
+		MinSize:   1024,
+		Level:     gzip.DefaultCompression,
+		SkipTypes: DefaultCompressSkipTypes,
+	}
+}
+
+// withDefaults fills empty options of [CompressConfig]
+func (c CompressConfig) withDefaults() CompressConfig {
+	if c.MinSize <= 0 {
+		c.MinSize = 1024
+	}
+	if c.NoCompression {
+		c.Level = gzip.NoCompression
+	} else if c.Level == gzip.NoCompression {
+		c.Level = gzip.DefaultCompression
+	}
+	if c.SkipTypes == nil {
+		c.SkipTypes = DefaultCompressSkipTypes
+	}
+	return c
+}
+
+// skips checks if content type should not be compressed
+func (c *CompressConfig) skips(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, t := range c.SkipTypes {
+		if t == mediaType || (strings.HasSuffix(t, "/*") &&
+			strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// compressEncodings defines supported encodings in order of preference
+var compressEncodings = []string{"gzip", "deflate"}
+
+// NegotiateEncoding finds preferred supported encoding from Accept-Encoding
+func NegotiateEncoding(r *http.Request) (string, bool) {
+	ranges := parseAccept(r.Header.Get("Accept-Encoding"))
+
+	best, quality := "", 0.0
+	for _, enc := range compressEncodings {
+		q, found := 0.0, false
+		for _, ar := range ranges {
+			if ar.mediaType == enc {
+				q, found = ar.quality, true
+				break
+			} else if ar.mediaType == "*/*" {
+				q, found = ar.quality, true
+			}
+		}
+
+		if found && q > quality {
+			best, quality = enc, q
+		}
+	}
+
+	return best, len(best) > 0
+}
+
+// compressWriter defines writer buffering body until compression is decided
+type compressWriter struct {
+	http.ResponseWriter
+	config     *CompressConfig
+	encoding   string
+	buf        []byte
+	writer     io.WriteCloser
+	statusCode int
+	decided    bool
+	rawSize    uint64
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	// informational responses are sent as is
+	if code < http.StatusOK {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.statusCode = code
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.rawSize += uint64(len(data))
+	if w.decided {
+		if w.writer != nil {
+			return w.writer.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buf = append(w.buf, data...)
+	if len(w.buf) >= w.config.MinSize {
+		err := w.decide(true)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+// decide sends header and buffered body with or without compression
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	header := w.Header()
+
+	if len(header.Get("Content-Type")) < 1 && len(w.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	compress = compress &&
+		len(header.Get("Content-Encoding")) < 1 &&
+		w.statusCode != http.StatusNoContent &&
+		w.statusCode != http.StatusNotModified &&
+		!w.config.skips(header.Get("Content-Type"))
+
+	if compress {
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		var err error
+		if w.encoding == "gzip" {
+			w.writer, err = gzip.NewWriterLevel(w.ResponseWriter, w.config.Level)
+		} else {
+			w.writer, err = flate.NewWriter(w.ResponseWriter, w.config.Level)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.statusCode > 0 {
+		w.ResponseWriter.WriteHeader(w.statusCode)
+	}
+
+	if len(w.buf) > 0 {
+		var err error
+		if w.writer != nil {
+			_, err = w.writer.Write(w.buf)
+		} else {
+			_, err = w.ResponseWriter.Write(w.buf)
+		}
+		w.buf = nil
+		return err
+	}
+	return nil
+}
+
+// close flushes pending body and finishes compression
+func (w *compressWriter) close() error {
+	if !w.decided {
+		err := w.decide(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.writer != nil {
+		err := w.writer.Close()
+		if sw, ok := findWriter[uncompressedSizeWriter](w.ResponseWriter); ok {
+			sw.addUncompressedSize(w.rawSize)
+		}
+		return err
+	}
+	return nil
+}
+
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+
+	if f, ok := w.writer.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Compress middleware builds handler to compress responses using
+// gzip or deflate negotiated from Accept-Encoding; panics on invalid level
+func Compress(configs ...*CompressConfig) NextHandler {
+	config := NewCompressConfig().withDefaults()
+	if len(configs) > 0 && configs[0] != nil {
+		config = configs[0].withDefaults()
+	}
+
+	if config.Level < gzip.HuffmanOnly || config.Level > gzip.BestCompression {
+		panic(fmt.Sprintf("invalid compression level: %d", config.Level))
+	}
+
+	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding, ok := NegotiateEncoding(r)
+		if !ok || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: w,
+			config:         &config,
+			encoding:       encoding,
+		}
+		defer func() {
+			_ = cw.close()
+		}()
+
+		next.ServeHTTP(cw, r)
+	})
+}
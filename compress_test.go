@@ -0,0 +1,157 @@
+package hndlor_test
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func CreateCompressTestRouter(log io.Writer) *hndlor.MuxRouter {
+	items := make([]hndlor.JSON, 200)
+	for i := range items {
+		items[i] = hndlor.JSON{"id": i, "name": fmt.Sprintf("item-%d", i)}
+	}
+
+	r := hndlor.Router().Use(hndlor.Logger(log), hndlor.Compress())
+	r.Handle("GET /list", hndlor.New(func() ([]hndlor.JSON, error) {
+		return items, nil
+	}))
+	r.HandleFunc("GET /small", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, "small")
+	})
+	r.HandleFunc("GET /image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(make([]byte, 4096))
+	})
+	r.HandleFunc("GET /encoded", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		_, _ = w.Write(make([]byte, 4096))
+	})
+	r.HandleFunc("GET /stream", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		http.NewResponseController(w).Flush()
+	})
+
+	return r
+}
+
+func TestCompressMiddleware(t *testing.T) {
+	var log strings.Builder
+	r := CreateCompressTestRouter(&log)
+
+	cases := []struct {
+		path     string
+		accept   string
+		encoding string
+	}{
+		{"/list", "gzip, deflate", "gzip"},
+		{"/list", "deflate, gzip;q=0.5", "deflate"},
+		{"/list", "*", "gzip"},
+		{"/list", "gzip;q=0, deflate;q=0", ""},
+		{"/list", "", ""},
+		{"/small", "gzip", ""},
+		{"/image", "gzip", ""},
+		{"/encoded", "gzip", "br"},
+		{"/stream", "gzip", "gzip"},
+	}
+
+	for _, c := range cases {
+		res, err := RunTestRequest(r, "GET", c.path, func(r *http.Request) {
+			r.Header.Set("Accept-Encoding", c.accept)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := res.Result()
+
+		if response.Header.Get("Content-Encoding") != c.encoding {
+			t.Errorf("%s (%s): expected encoding %q but got %q", c.path, c.accept, c.encoding, response.Header.Get("Content-Encoding"))
+			continue
+		}
+		if response.Header.Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s: missing vary header", c.path)
+		}
+
+		var body io.Reader = response.Body
+		switch c.encoding {
+		case "gzip":
+			body, err = gzip.NewReader(response.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+		case "deflate":
+			body = flate.NewReader(response.Body)
+		}
+
+		data, err := io.ReadAll(body)
+		if err != nil {
+			t.Errorf("%s: %s", c.path, err)
+		} else if c.path == "/list" && !strings.Contains(string(data), `"name":"item-199"`) {
+			t.Errorf("%s: invalid body", c.path)
+		} else if c.path == "/stream" && (string(data) != "chunk" || !res.Flushed) {
+			t.Errorf("%s: stream was not flushed: %q", c.path, data)
+		}
+	}
+
+	line := strings.Split(log.String(), "\n")[0]
+	var status int
+	var size, raw uint64
+	_, err := fmt.Sscanf(line[strings.Index(line, "S "):], "S %d, L %d, U %d", &status, &size, &raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size < 1 || raw <= size {
+		t.Errorf("logger should report compressed and uncompressed size: %s", line)
+	}
+}
+
+func TestCompressLevel(t *testing.T) {
+	payload := strings.Repeat("a", 4096)
+	compressed := func(config *hndlor.CompressConfig, path string) int {
+		r := hndlor.Router().Use(hndlor.Compress(config))
+		r.HandleFunc("GET /text", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(payload))
+		})
+		r.HandleFunc("GET /image", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte(payload))
+		})
+
+		res, err := RunTestRequest(r, "GET", path, func(r *http.Request) {
+			r.Header.Set("Accept-Encoding", "gzip")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Header().Get("Content-Encoding") != "gzip" {
+			return -1
+		}
+		return res.Body.Len()
+	}
+
+	// partial config keeps default level and skip types
+	if size := compressed(&hndlor.CompressConfig{MinSize: 512}, "/text"); size < 0 || size >= len(payload) {
+		t.Errorf("expected compressed body with partial config: %d bytes", size)
+	}
+	if size := compressed(&hndlor.CompressConfig{MinSize: 512}, "/image"); size != -1 {
+		t.Errorf("expected image to skip compression with partial config: %d bytes", size)
+	}
+
+	// stored blocks are larger than the payload when compression is disabled
+	if size := compressed(&hndlor.CompressConfig{NoCompression: true}, "/text"); size <= len(payload) {
+		t.Errorf("expected uncompressed gzip stream: %d bytes", size)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected invalid compression level to panic")
+		}
+	}()
+	hndlor.Compress(&hndlor.CompressConfig{Level: 42})
+}
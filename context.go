@@ -1,61 +1,113 @@
-package hndlor
-
-import (
-	"context"
-	"maps"
-	"net/http"
-)
-
-// ContextValue defines custom type for [context.Context] key
-type ContextValue int
-
-const (
-	ContextValueDefault ContextValue = iota // default context key for data
-	ContextValueJSON
-)
-
-// GetAllData retrieves saved [JSON] saved in default context data
-func GetAllData(r *http.Request) JSON {
-	var data JSON
-	raw := r.Context().Value(ContextValueDefault)
-	if raw == nil {
-		data = JSON{}
-	} else {
-		data = raw.(JSON)
-	}
-
-	return data
-}
-
-// GetData retrieves specific key from saved default context data
-func GetData[T any](r *http.Request, key string, fb T) (T, error) {
-	data := GetAllData(r)
-	v, ok := data[key]
-	if ok {
-		return v.(T), nil
-	}
-	return fb, Errorf("unable to find context data: %s", key).Server().Path(r.URL.Path)
-}
-
-// PatchValue writes key/value to default context data
-func PatchValue(r *http.Request, key string, value any) *http.Request {
-	data := GetAllData(r)
-	data[key] = value
-
-	return Patch(r, ContextValueDefault, data)
-}
-
-// PatchValue writes [JSON] to default context data
-func PatchMap(r *http.Request, value JSON) *http.Request {
-	data := GetAllData(r)
-	maps.Copy(data, value)
-
-	return Patch(r, ContextValueDefault, data)
-}
-
-// Patch updates the request context with new key/value data
-func Patch(r *http.Request, key any, value any) *http.Request {
-	return r.WithContext(
-		context.WithValue(r.Context(), key, value),
-	)
-}
+package hndlor
+
+import (
+	"context"
+	"maps"
+	"net/http"
+)
+
+// ContextValue defines custom type for [context.Context] key
+type ContextValue int
+
+const (
+	ContextValueDefault ContextValue = iota // default context key for data
+	ContextValueJSON
+	ContextValueBodyConfig
+	ContextValueRaw
+	ContextValueBody
+	ContextValueRequestID
+	ContextValueClaims
+)
+
+// Key defines typed key for default context data
+type Key[T any] struct {
+	name string
+}
+
+// Name returns the name of key in default context data
+func (k Key[T]) Name() string {
+	return k.name
+}
+
+// Resolver creates [Context] value resolver for the key
+func (k Key[T]) Resolver() *Value[T] {
+	return Context[T](k.name)
+}
+
+// NewKey creates typed key for default context data
+func NewKey[T any](name string) Key[T] {
+	return Key[T]{name: name}
+}
+
+// SetKey writes typed value to default context data
+func SetKey[T any](r *http.Request, key Key[T], value T) *http.Request {
+	return PatchValue(r, key.name, value)
+}
+
+// GetKey retrieves typed value from default context data
+func GetKey[T any](r *http.Request, key Key[T]) (T, error) {
+	var fb T
+	return GetData(r, key.name, fb)
+}
+
+// contextData retrieves shared default context data; must not be mutated
+func contextData(r *http.Request) JSON {
+	raw := r.Context().Value(ContextValueDefault)
+	if raw == nil {
+		return nil
+	}
+
+	return raw.(JSON)
+}
+
+// GetAllData retrieves copy of [JSON] saved in default context data
+func GetAllData(r *http.Request) JSON {
+	data := maps.Clone(contextData(r))
+	if data == nil {
+		data = JSON{}
+	}
+
+	return data
+}
+
+// GetData retrieves specific key from saved default context data
+func GetData[T any](r *http.Request, key string, fb T) (T, error) {
+	v, ok := contextData(r)[key]
+	if !ok {
+		return fb, Errorf("unable to find context data: %s", key).Server().Path(r.URL.Path)
+	}
+
+	if v == nil {
+		return fb, nil
+	}
+
+	tv, ok := v.(T)
+	if !ok {
+		return fb, Errorf("invalid context data type: %s is %T", key, v).
+			Server().
+			Path(r.URL.Path).
+			Reason("context_type_mismatch")
+	}
+
+	return tv, nil
+}
+
+// PatchValue writes key/value to copy of default context data
+func PatchValue(r *http.Request, key string, value any) *http.Request {
+	return PatchMap(r, JSON{key: value})
+}
+
+// PatchMap writes [JSON] to copy of default context data
+func PatchMap(r *http.Request, value JSON) *http.Request {
+	data := GetAllData(r)
+	maps.Copy(data, value)
+
+	return Patch(r, ContextValueDefault, data)
+}
+
+// Patch updates the request context with new key/value data
+func Patch(r *http.Request, key any, value any) *http.Request {
+	return r.WithContext(
+		context.WithValue(r.Context(), key, value),
+	)
+}
@@ -0,0 +1,258 @@
+package hndlor
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// converterRegistry holds the registered value converters by type
+type converterRegistry struct {
+	sync.RWMutex
+	items map[reflect.Type]func(string) (any, error)
+}
+
+// registered custom value converters
+var converters = &converterRegistry{
+	items: make(map[reflect.Type]func(string) (any, error)),
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// RegisterConverter adds or replaces converter used by value
+// resolvers to read string values as type [T]
+func RegisterConverter[T any](cb func(string) (T, error)) {
+	converters.Lock()
+	defer converters.Unlock()
+
+	converters.items[reflect.TypeOf((*T)(nil)).Elem()] = func(s string) (any, error) {
+		return cb(s)
+	}
+}
+
+// findConverter retrieves registered converter for type
+func findConverter(tp reflect.Type) (func(string) (any, error), bool) {
+	converters.RLock()
+	defer converters.RUnlock()
+
+	cb, ok := converters.items[tp]
+	return cb, ok
+}
+
+// isScalar checks if struct like type is parsed from single value
+func isScalar(tp reflect.Type) bool {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+
+	if _, ok := findConverter(tp); ok {
+		return true
+	}
+	return tp == timeType || reflect.PointerTo(tp).Implements(textUnmarshalerType)
+}
+
+// stringify formats raw value as string for parsing
+func stringify(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case json.Number:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(value)
+}
+
+// convertError creates error for failed conversion
+func convertError(value any, tp reflect.Type, err error) error {
+	if err != nil {
+		return Errorf("unable to convert [%v] to %s: %s", value, tp, err).Reason("value_invalid")
+	}
+	return Errorf("unable to convert [%v] to %s", value, tp).Reason("value_invalid")
+}
+
+// convertValue converts raw value to [reflect.Value] of provided type
+func convertValue(tp reflect.Type, value any) (reflect.Value, error) {
+	if value == nil {
+		return reflect.Zero(tp), nil
+	}
+
+	vt := reflect.TypeOf(value)
+	if vt == tp {
+		return reflect.ValueOf(value), nil
+	}
+
+	if cb, ok := findConverter(tp); ok {
+		out, err := cb(stringify(value))
+		if err != nil {
+			return reflect.Value{}, convertError(value, tp, err)
+		}
+		return reflect.ValueOf(out), nil
+	}
+
+	if tp.Kind() == reflect.Interface {
+		if vt.Implements(tp) {
+			rv := reflect.New(tp).Elem()
+			rv.Set(reflect.ValueOf(value))
+			return rv, nil
+		}
+		return reflect.Value{}, convertError(value, tp, nil)
+	}
+
+	if tp.Kind() == reflect.Ptr {
+		el, err := convertValue(tp.Elem(), value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		ptr := reflect.New(tp.Elem())
+		ptr.Elem().Set(el)
+		return ptr, nil
+	}
+
+	str := stringify(value)
+	rv := reflect.New(tp).Elem()
+
+	switch {
+	case tp == timeType:
+		if unix, err := strconv.ParseInt(str, 10, 64); err == nil {
+			rv.Set(reflect.ValueOf(time.Unix(unix, 0).UTC()))
+			return rv, nil
+		}
+	case tp == durationType:
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			return reflect.Value{}, convertError(value, tp, err)
+		}
+		rv.SetInt(int64(d))
+		return rv, nil
+	}
+
+	if reflect.PointerTo(tp).Implements(textUnmarshalerType) {
+		err := rv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+		if err != nil {
+			return reflect.Value{}, convertError(value, tp, err)
+		}
+		return rv, nil
+	}
+
+	var err error
+	switch tp.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var val int64
+		val, err = strconv.ParseInt(str, 10, tp.Bits())
+		rv.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var val uint64
+		val, err = strconv.ParseUint(str, 10, tp.Bits())
+		rv.SetUint(val)
+	case reflect.Float32, reflect.Float64:
+		var val float64
+		val, err = strconv.ParseFloat(str, tp.Bits())
+		rv.SetFloat(val)
+	case reflect.Complex64, reflect.Complex128:
+		var val complex128
+		val, err = strconv.ParseComplex(str, tp.Bits())
+		rv.SetComplex(val)
+	case reflect.Bool:
+		var val bool
+		val, err = strconv.ParseBool(str)
+		rv.SetBool(val)
+	case reflect.String:
+		rv.SetString(str)
+	case reflect.Slice:
+		return convertSlice(tp, value)
+	case reflect.Struct:
+		if data, ok := value.(map[string]any); ok {
+			err = bindMap(rv, data)
+		} else if data, ok := value.(JSON); ok {
+			err = bindMap(rv, data)
+		} else {
+			err = StructToStruct(value, rv.Addr().Interface())
+		}
+	case reflect.Map, reflect.Array:
+		err = StructToStruct(value, rv.Addr().Interface())
+	default:
+		return reflect.Value{}, convertError(value, tp, nil)
+	}
+
+	if err != nil {
+		return reflect.Value{}, convertError(value, tp, err)
+	}
+	return rv, nil
+}
+
+// convertSlice converts list value to slice of provided type
+func convertSlice(tp reflect.Type, value any) (reflect.Value, error) {
+	if tp.Elem().Kind() == reflect.Uint8 {
+		if str, ok := value.(string); ok {
+			return reflect.ValueOf([]byte(str)).Convert(tp), nil
+		}
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		rv = reflect.ValueOf([]any{value})
+	}
+
+	list := reflect.MakeSlice(tp, 0, rv.Len())
+	for i := range rv.Len() {
+		el, err := convertValue(tp.Elem(), rv.Index(i).Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		list = reflect.Append(list, el)
+	}
+
+	return list, nil
+}
+
+// bindMap sets struct fields from map data converting every
+// value to the field type; keys are matched by binding name,
+// json tag name or case-insensitive field name
+func bindMap(rv reflect.Value, data map[string]any) error {
+	tp := rv.Type()
+
+	for _, fb := range bindingPlanFor(tp).fields {
+		f := tp.FieldByIndex(fb.index)
+
+		value, ok := data[fb.name]
+		if !ok {
+			jName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			value, ok = data[jName]
+		}
+		if !ok {
+			for key, v := range data {
+				if strings.EqualFold(key, f.Name) {
+					value, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		fv, err := convertValue(fb.rType, value)
+		if err != nil {
+			return err
+		}
+		rv.FieldByIndex(fb.index).Set(fv)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package hndlor
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// CookieCodec defines interface to encode/decode cookie values
+type CookieCodec interface {
+
+	// Encode secures the cookie value for the cookie name
+	Encode(name string, value string) (string, error)
+
+	// Decode verifies and returns original cookie value for the cookie name
+	Decode(name string, value string) (string, error)
+}
+
+// errInvalidCookie creates error for tampered or unreadable cookie
+func errInvalidCookie() *ResponseError {
+	return Error("invalid cookie value").
+		Status(http.StatusBadRequest).
+		Reason("cookie_invalid")
+}
+
+// cookieEncoding used to encode cookie values
+var cookieEncoding = base64.RawURLEncoding
+
+// signedCodec signs cookie values using HMAC-SHA256
+type signedCodec struct {
+	keys [][]byte
+}
+
+func (c signedCodec) sign(key []byte, name string, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name + "=" + value))
+	return mac.Sum(nil)
+}
+
+func (c signedCodec) Encode(name string, value string) (string, error) {
+	sig := c.sign(c.keys[0], name, value)
+	return cookieEncoding.EncodeToString([]byte(value)) + "." + cookieEncoding.EncodeToString(sig), nil
+}
+
+func (c signedCodec) Decode(name string, value string) (string, error) {
+	raw, rawSig, ok := strings.Cut(value, ".")
+	if !ok {
+		return "", errInvalidCookie()
+	}
+
+	bt, err := cookieEncoding.DecodeString(raw)
+	if err != nil {
+		return "", errInvalidCookie()
+	}
+	sig, err := cookieEncoding.DecodeString(rawSig)
+	if err != nil {
+		return "", errInvalidCookie()
+	}
+
+	for _, key := range c.keys {
+		if hmac.Equal(sig, c.sign(key, name, string(bt))) {
+			return string(bt), nil
+		}
+	}
+
+	return "", errInvalidCookie()
+}
+
+// encryptedCodec encrypts cookie values using AES-GCM
+type encryptedCodec struct {
+	aeads []cipher.AEAD
+}
+
+func (c encryptedCodec) Encode(name string, value string) (string, error) {
+	aead := c.aeads[0]
+	nonce := make([]byte, aead.NonceSize())
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return cookieEncoding.EncodeToString(sealed), nil
+}
+
+func (c encryptedCodec) Decode(name string, value string) (string, error) {
+	bt, err := cookieEncoding.DecodeString(value)
+	if err != nil {
+		return "", errInvalidCookie()
+	}
+
+	for _, aead := range c.aeads {
+		size := aead.NonceSize()
+		if len(bt) < size {
+			continue
+		}
+
+		plain, err := aead.Open(nil, bt[:size], bt[size:], []byte(name))
+		if err == nil {
+			return string(plain), nil
+		}
+	}
+
+	return "", errInvalidCookie()
+}
+
+// minSignedKeySize defines minimum key size for HMAC-SHA256 signing
+const minSignedKeySize = 32
+
+// NewSignedCodec creates [CookieCodec] that signs cookie values with
+// keys of at least 32 bytes; first key signs new cookies and all keys
+// verify for rotation
+func NewSignedCodec(keys ...[]byte) (CookieCodec, error) {
+	if len(keys) < 1 {
+		return nil, errors.New("signed cookie codec requires at least one key")
+	}
+
+	for _, key := range keys {
+		if len(key) < minSignedKeySize {
+			return nil, fmt.Errorf("signed cookie codec requires keys of at least %d bytes", minSignedKeySize)
+		}
+	}
+
+	return signedCodec{keys}, nil
+}
+
+// NewEncryptedCodec creates [CookieCodec] that encrypts cookie values
+// with AES-GCM using 16, 24 or 32 bytes keys; first key encrypts new
+// cookies and all keys decrypt for rotation
+func NewEncryptedCodec(keys ...[]byte) (CookieCodec, error) {
+	if len(keys) < 1 {
+		return nil, errors.New("encrypted cookie codec requires at least one key")
+	}
+
+	aeads := make([]cipher.AEAD, len(keys))
+	for i, key := range keys {
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		aeads[i], err = cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return encryptedCodec{aeads}, nil
+}
+
+// EncodeCookie creates copy of cookie with value encoded by [CookieCodec]
+func EncodeCookie(codec CookieCodec, c *http.Cookie) (*http.Cookie, error) {
+	value, err := codec.Encode(c.Name, c.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	nc := *c
+	nc.Value = value
+	return &nc, nil
+}
+
+// ReadCookie reads cookie value from request decoded by optional [CookieCodec]
+func ReadCookie(r *http.Request, name string, codec CookieCodec) (string, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	if codec != nil {
+		return codec.Decode(c.Name, c.Value)
+	}
+	return c.Value, nil
+}
+
+// SetCookie writes cookie to [http.ResponseWriter] encoded by [CookieCodec]
+func SetCookie(w http.ResponseWriter, c *http.Cookie, codec CookieCodec) error {
+	nc, err := EncodeCookie(codec, c)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, nc)
+	return nil
+}
+
+// Cookie defines value resolver from request cookies
+func Cookie[T any](name string) *Value[T] {
+	return NewValue[T](name, ValueSourceCookie)
+}
@@ -0,0 +1,92 @@
+package hndlor_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+type TestSession struct {
+	SID  string
+	Lang string
+}
+
+func TestCookieResolve(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.AddCookie(&http.Cookie{Name: "sid", Value: "abc"})
+	req.AddCookie(&http.Cookie{Name: "lang", Value: "en"})
+	req.AddCookie(&http.Cookie{Name: "visits", Value: "3"})
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Cookie[string]("sid"),
+		hndlor.Cookie[int64]("visits"),
+		hndlor.StructFrom[TestSession](hndlor.ValueSourceCookie).As("session"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session := values["session"].(TestSession)
+	if values["sid"] != "abc" || values["visits"] != int64(3) || session.Lang != "en" {
+		t.Error("unable to resolve cookie values")
+	}
+}
+
+func TestCookieCodecs(t *testing.T) {
+	oldKey := []byte("0123456789abcdef0123456789abcdef")
+	newKey := []byte("fedcba9876543210fedcba9876543210")
+
+	for _, key := range [][]byte{nil, {}, []byte("0123456789abcdef")} {
+		if _, err := hndlor.NewSignedCodec(key); err == nil {
+			t.Errorf("expected signed codec to reject %d bytes key", len(key))
+		}
+	}
+
+	signed, _ := hndlor.NewSignedCodec(oldKey)
+	rotatedSigned, _ := hndlor.NewSignedCodec(newKey, oldKey)
+	encrypted, _ := hndlor.NewEncryptedCodec(oldKey)
+	rotatedEncrypted, err := hndlor.NewEncryptedCodec(newKey, oldKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codecs := map[string][2]hndlor.CookieCodec{
+		"signed":    {signed, rotatedSigned},
+		"encrypted": {encrypted, rotatedEncrypted},
+	}
+
+	for name, codec := range codecs {
+		c, err := hndlor.EncodeCookie(codec[0], &http.Cookie{Name: "token", Value: "secret-value"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.AddCookie(c)
+
+		token, err := hndlor.Cookie[string]("token").Codec(codec[1]).Resolve(nil, req)
+		if err != nil || token != "secret-value" {
+			t.Errorf("unable to resolve %s cookie", name)
+		}
+
+		req, _ = http.NewRequest("GET", "/", nil)
+		req.AddCookie(&http.Cookie{Name: "token", Value: c.Value + "x"})
+
+		_, err = hndlor.Cookie[string]("token").Codec(codec[1]).Resolve(nil, req)
+		if re, ok := err.(*hndlor.ResponseError); !ok {
+			t.Errorf("unable to reject tampered %s cookie", name)
+		} else {
+			re.Status(http.StatusTeapot)
+		}
+
+		// decorating returned error must not change later errors
+		_, err = hndlor.Cookie[string]("token").Codec(codec[1]).Resolve(nil, req)
+		if re, ok := err.(*hndlor.ResponseError); !ok || re.ResponseStatus() != http.StatusBadRequest {
+			t.Errorf("tampered %s cookie error should be fresh: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package hndlor
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSPolicy defines options for [CORS] middleware
+type CORSPolicy struct {
+
+	// allowed origins; supports "*", exact and wildcard subdomain
+	// i.e. https://*.example.com; "*" cannot be used with Credentials,
+	// use AllowOrigin to explicitly validate credentialed origins
+	Origins []string
+
+	// allowed origins matching any pattern
+	OriginPatterns []*regexp.Regexp
+
+	// custom origin validator checked after other rules
+	AllowOrigin func(origin string, r *http.Request) bool
+
+	// allowed methods; default GET, HEAD and POST
+	Methods []string
+
+	// allowed request headers; empty or "*" allows requested headers
+	Headers []string
+
+	// response headers exposed to client
+	ExposeHeaders []string
+
+	// allow cookies and credentials
+	Credentials bool
+
+	// how long preflight response can be cached
+	MaxAge time.Duration
+}
+
+// corsOrigin defines compiled wildcard origin
+type corsOrigin struct {
+	prefix string
+	suffix string
+}
+
+// matches checks if origin has at least one subdomain for wildcard
+func (o corsOrigin) matches(origin string) bool {
+	return len(origin) > len(o.prefix)+len(o.suffix) &&
+		strings.HasPrefix(origin, o.prefix) &&
+		strings.HasSuffix(origin, o.suffix)
+}
+
+// allowsOrigin checks if origin is allowed by the policy
+func (p *CORSPolicy) allowsOrigin(origin string, r *http.Request, anyOrigin bool, exact []string, wildcards []corsOrigin) bool {
+	if anyOrigin || slices.Contains(exact, origin) {
+		return true
+	}
+
+	for _, w := range wildcards {
+		if w.matches(origin) {
+			return true
+		}
+	}
+
+	for _, re := range p.OriginPatterns {
+		if re.MatchString(origin) {
+			return true
+		}
+	}
+
+	return p.AllowOrigin != nil && p.AllowOrigin(origin, r)
+}
+
+// allowsHeaders checks if all requested headers are allowed
+func (p *CORSPolicy) allowsHeaders(requested string, anyHeader bool) bool {
+	if anyHeader || len(requested) < 1 {
+		return true
+	}
+
+	for _, h := range strings.Split(requested, ",") {
+		h = strings.TrimSpace(h)
+		if len(h) > 0 && !slices.ContainsFunc(p.Headers, func(a string) bool {
+			return strings.EqualFold(a, h)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// CORS middleware builds handler to apply cross-origin policy and
+// answer preflight requests before routing; panics when "*" origin
+// is combined with credentials
+func CORS(policy *CORSPolicy) NextHandler {
+	if policy.Credentials && slices.Contains(policy.Origins, "*") {
+		panic("cors: wildcard origin cannot allow credentials; use AllowOrigin")
+	}
+
+	var anyOrigin bool
+	exact := make([]string, 0)
+	wildcards := make([]corsOrigin, 0)
+	for _, o := range policy.Origins {
+		if o == "*" {
+			anyOrigin = true
+		} else if i := strings.Index(o, "*"); i >= 0 {
+			wildcards = append(wildcards, corsOrigin{o[:i], o[i+1:]})
+		} else {
+			exact = append(exact, o)
+		}
+	}
+
+	methods := policy.Methods
+	if len(methods) < 1 {
+		methods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+	anyHeader := len(policy.Headers) < 1 || slices.Contains(policy.Headers, "*")
+
+	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		origin := r.Header.Get("Origin")
+		preflight := r.Method == http.MethodOptions &&
+			len(r.Header.Get("Access-Control-Request-Method")) > 0
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		allowed := len(origin) > 0 &&
+			policy.allowsOrigin(origin, r, anyOrigin, exact, wildcards)
+
+		if allowed {
+			if anyOrigin {
+				header.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				header.Set("Access-Control-Allow-Origin", origin)
+			}
+			if policy.Credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if !preflight {
+			if allowed && len(policy.ExposeHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposeHeaders, ", "))
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		method := r.Header.Get("Access-Control-Request-Method")
+		requested := r.Header.Get("Access-Control-Request-Headers")
+		if allowed && slices.Contains(methods, method) && policy.allowsHeaders(requested, anyHeader) {
+			header.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+			if len(requested) > 0 {
+				if anyHeader {
+					header.Set("Access-Control-Allow-Headers", requested)
+				} else {
+					header.Set("Access-Control-Allow-Headers", strings.Join(policy.Headers, ", "))
+				}
+			}
+			if policy.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+			}
+		} else {
+			header.Del("Access-Control-Allow-Origin")
+			header.Del("Access-Control-Allow-Credentials")
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
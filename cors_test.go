@@ -0,0 +1,131 @@
+package hndlor_test
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func CreateCORSTestRouter() *hndlor.MuxRouter {
+	r := hndlor.Router()
+
+	public := hndlor.SubRouter("/public").Use(hndlor.CORS(&hndlor.CORSPolicy{
+		Origins: []string{"*"},
+	}))
+	public.HandleFunc("GET /info", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, "info")
+	})
+	public.MountTo(r.Mux())
+
+	admin := hndlor.SubRouter("/admin").Use(hndlor.CORS(&hndlor.CORSPolicy{
+		Origins:        []string{"https://admin.example.com", "https://*.example.org"},
+		OriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^https://staff-\d+\.example\.net$`)},
+		AllowOrigin: func(origin string, r *http.Request) bool {
+			return origin == "https://partner.test"
+		},
+		Methods:       []string{http.MethodPost},
+		Headers:       []string{"Content-Type", "X-Api-Token"},
+		ExposeHeaders: []string{"X-Request-Id"},
+		Credentials:   true,
+		MaxAge:        10 * time.Minute,
+	}))
+	admin.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, "ok")
+	})
+	admin.MountTo(r.Mux())
+
+	return r
+}
+
+func TestCORSPreflight(t *testing.T) {
+	r := CreateCORSTestRouter()
+
+	preflight := func(origin string, headers string) http.Header {
+		res, err := RunTestRequest(r, "OPTIONS", "/admin/login", func(r *http.Request) {
+			r.Header.Set("Origin", origin)
+			r.Header.Set("Access-Control-Request-Method", "POST")
+			r.Header.Set("Access-Control-Request-Headers", headers)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = InvalidateTestResultStatus(res.Result(), 204)
+		if err != nil {
+			t.Error(err)
+		}
+		return res.Result().Header
+	}
+
+	for _, origin := range []string{
+		"https://admin.example.com",
+		"https://app.example.org",
+		"https://staff-12.example.net",
+		"https://partner.test",
+	} {
+		h := preflight(origin, "content-type, x-api-token")
+		if h.Get("Access-Control-Allow-Origin") != origin ||
+			h.Get("Access-Control-Allow-Methods") != "POST" ||
+			h.Get("Access-Control-Allow-Credentials") != "true" ||
+			h.Get("Access-Control-Max-Age") != "600" ||
+			!strings.Contains(h.Get("Access-Control-Allow-Headers"), "X-Api-Token") {
+			t.Errorf("invalid preflight response for %s: %v", origin, h)
+		}
+	}
+
+	for _, origin := range []string{"https://evil.test", "https://example.org"} {
+		h := preflight(origin, "")
+		if len(h.Get("Access-Control-Allow-Origin")) > 0 {
+			t.Errorf("origin should not be allowed: %s", origin)
+		}
+	}
+
+	h := preflight("https://admin.example.com", "x-unknown")
+	if len(h.Get("Access-Control-Allow-Origin")) > 0 {
+		t.Error("unknown request header should not be allowed")
+	}
+}
+
+func TestCORSRequest(t *testing.T) {
+	r := CreateCORSTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/public/info", func(r *http.Request) {
+		r.Header.Set("Origin", "https://any.test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := res.Result().Header
+	if h.Get("Access-Control-Allow-Origin") != "*" || h.Get("Vary") != "Origin" {
+		t.Errorf("invalid public cors response: %v", h)
+	}
+
+	res, err = RunTestRequest(r, "POST", "/admin/login", func(r *http.Request) {
+		r.Header.Set("Origin", "https://admin.example.com")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h = res.Result().Header
+	if h.Get("Access-Control-Allow-Origin") != "https://admin.example.com" ||
+		h.Get("Access-Control-Expose-Headers") != "X-Request-Id" {
+		t.Errorf("invalid admin cors response: %v", h)
+	}
+}
+
+func TestCORSWildcardCredentials(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected wildcard origin with credentials to panic")
+		}
+	}()
+
+	hndlor.CORS(&hndlor.CORSPolicy{
+		Origins:     []string{"*"},
+		Credentials: true,
+	})
+}
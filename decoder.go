@@ -0,0 +1,267 @@
+package hndlor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// BodyDecoder defines function signature for request body decoder
+//
+// Decoder either returns decoded data to be cached on request
+// context or nil when request is parsed in place (i.e. forms)
+type BodyDecoder func(*http.Request) (any, error)
+
+// decoderRegistry holds the registered [BodyDecoder] by media type
+type decoderRegistry struct {
+	sync.RWMutex
+	items map[string]BodyDecoder
+}
+
+// registered request body decoders
+var decoders = &decoderRegistry{
+	items: map[string]BodyDecoder{
+		ContentTypeJSON:       DecodeJSON,
+		ContentTypeXML:        DecodeXML,
+		"text/xml":            DecodeXML,
+		ContentTypeURLEncoded: DecodeForm,
+		ContentTypeMultipart:  DecodeMultipart,
+	},
+}
+
+// RegisterBodyDecoder adds or replaces [BodyDecoder] for media type
+func RegisterBodyDecoder(mediaType string, dec BodyDecoder) {
+	decoders.Lock()
+	defer decoders.Unlock()
+
+	decoders.items[strings.ToLower(mediaType)] = dec
+}
+
+// MediaType reads the media type from Content-Type header without parameters
+func MediaType(r *http.Request) string {
+	cType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(cType)
+	if err != nil {
+		mediaType, _, _ = strings.Cut(cType, ";")
+	}
+
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// BodyDecoderFor finds the [BodyDecoder] for request's Content-Type
+//
+// Structured syntax suffixes like +json and +xml fallback to their
+// base decoders and empty Content-Type is treated as json
+func BodyDecoderFor(r *http.Request) (BodyDecoder, bool) {
+	decoders.RLock()
+	defer decoders.RUnlock()
+
+	mediaType := MediaType(r)
+	if len(mediaType) < 1 {
+		mediaType = ContentTypeJSON
+	}
+
+	dec, ok := decoders.items[mediaType]
+	if !ok {
+		switch {
+		case strings.HasSuffix(mediaType, "+json"):
+			dec, ok = decoders.items[ContentTypeJSON]
+		case strings.HasSuffix(mediaType, "+xml"):
+			dec, ok = decoders.items[ContentTypeXML]
+		}
+	}
+
+	return dec, ok
+}
+
+// isJSONBody checks if request body is json by its media type
+func isJSONBody(r *http.Request) bool {
+	mediaType := MediaType(r)
+	return len(mediaType) < 1 || mediaType == ContentTypeJSON ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+// emptyBody checks if request body is unavailable
+func emptyBody(r *http.Request) bool {
+	return r.Body == nil || r.Body == http.NoBody
+}
+
+// DecodeJSON decodes json request body keeping numbers as [json.Number]
+func DecodeJSON(r *http.Request) (any, error) {
+	if emptyBody(r) {
+		return nil, nil
+	}
+
+	var data any
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	err := dec.Decode(&data)
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+// XMLTextKey defines key of element text when element has attributes
+const XMLTextKey = "#text"
+
+// DecodeXML decodes xml request body into [JSON] where
+// child elements and attributes of root element are the keys
+// and text of elements with attributes is kept under [XMLTextKey]
+func DecodeXML(r *http.Request) (any, error) {
+	if emptyBody(r) {
+		return nil, nil
+	}
+
+	dec := xml.NewDecoder(r.Body)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if ok {
+			return decodeXMLElement(dec, start)
+		}
+	}
+}
+
+// decodeXMLElement reads the xml element as [JSON] or
+// string when element doesn't have children
+func decodeXMLElement(dec *xml.Decoder, start xml.StartElement) (any, error) {
+	data := make(JSON)
+	for _, attr := range start.Attr {
+		data[attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(dec, t)
+			if err != nil {
+				return nil, err
+			}
+
+			key := t.Name.Local
+			switch existing := data[key].(type) {
+			case nil:
+				data[key] = child
+			case []any:
+				data[key] = append(existing, child)
+			default:
+				data[key] = []any{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(data) < 1 {
+				return content, nil
+			} else if len(content) > 0 {
+				data[XMLTextKey] = content
+			}
+			return data, nil
+		}
+	}
+}
+
+// DecodeForm parses url encoded request body in place
+func DecodeForm(r *http.Request) (any, error) {
+	return nil, r.ParseForm()
+}
+
+// DecodeMultipart parses multipart request body in place
+// with memory limit from [BodyConfig]
+func DecodeMultipart(r *http.Request) (any, error) {
+	config := GetBodyConfig(r)
+	if config.MaxFiles < 1 {
+		return nil, r.ParseMultipartForm(config.MaxMemory)
+	}
+
+	err := r.ParseForm()
+	if err != nil {
+		return nil, err
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	// parts are streamed through file counter so parsing
+	// stops before spooling files beyond the limit
+	pr, pw := io.Pipe()
+	defer pr.Close()
+
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(copyParts(mr, mw, config))
+	}()
+
+	form, err := multipart.NewReader(pr, mw.Boundary()).ReadForm(config.MaxMemory)
+	if err != nil {
+		var rErr *ResponseError
+		if errors.As(err, &rErr) {
+			return nil, rErr
+		}
+		return nil, err
+	}
+
+	if r.PostForm == nil {
+		r.PostForm = make(url.Values)
+	}
+	for k, v := range form.Value {
+		r.Form[k] = append(r.Form[k], v...)
+		r.PostForm[k] = append(r.PostForm[k], v...)
+	}
+	r.MultipartForm = form
+
+	return nil, nil
+}
+
+// copyParts copies multipart parts until [BodyConfig] file limit is exceeded
+func copyParts(mr *multipart.Reader, mw *multipart.Writer, config *BodyConfig) error {
+	files := 0
+	for {
+		p, err := mr.NextRawPart()
+		if errors.Is(err, io.EOF) {
+			return mw.Close()
+		} else if err != nil {
+			return err
+		}
+
+		if len(p.FileName()) > 0 {
+			files++
+			if files > config.MaxFiles {
+				return config.tooManyFiles()
+			}
+		}
+
+		w, err := mw.CreatePart(p.Header)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(w, p)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package hndlor
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoder defines function signature for response data encoder
+type Encoder func(io.Writer, any) error
+
+// encoderRegistry holds the registered [Encoder] by media type
+type encoderRegistry struct {
+	sync.RWMutex
+	types []string
+	items map[string]Encoder
+}
+
+// registered response encoders in order of preference
+var encoders = &encoderRegistry{
+	types: []string{ContentTypeJSON, ContentTypeXML, ContentTypeText, ContentTypeURLEncoded},
+	items: map[string]Encoder{
+		ContentTypeJSON:       EncodeJSON,
+		ContentTypeXML:        EncodeXML,
+		ContentTypeText:       EncodeText,
+		ContentTypeURLEncoded: EncodeForm,
+	},
+}
+
+// acceptRange defines single media range of Accept header
+type acceptRange struct {
+	mediaType string
+	quality   float64
+	index     int
+}
+
+// specificity returns the precedence of media range
+func (a acceptRange) specificity() int {
+	switch {
+	case a.mediaType == "*/*":
+		return 0
+	case strings.HasSuffix(a.mediaType, "/*"):
+		return 1
+	}
+	return 2
+}
+
+// matches checks if media range accepts the media type
+func (a acceptRange) matches(mediaType string) bool {
+	switch a.specificity() {
+	case 0:
+		return true
+	case 1:
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(a.mediaType, "*"))
+	}
+	return a.mediaType == mediaType
+}
+
+// RegisterEncoder adds or replaces [Encoder] for media type
+func RegisterEncoder(mediaType string, enc Encoder) {
+	encoders.Lock()
+	defer encoders.Unlock()
+
+	mediaType = strings.ToLower(mediaType)
+	if !slices.Contains(encoders.types, mediaType) {
+		encoders.types = append(encoders.types, mediaType)
+	}
+	encoders.items[mediaType] = enc
+}
+
+// parseAccept reads media ranges from Accept header
+func parseAccept(header string) []acceptRange {
+	ranges := make([]acceptRange, 0)
+
+	for i, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if len(mediaType) < 1 {
+			continue
+		}
+		if mediaType == "*" {
+			mediaType = "*/*"
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(key, "q") {
+				q, err := strconv.ParseFloat(value, 64)
+				if err == nil {
+					quality = q
+				}
+			}
+		}
+
+		ranges = append(ranges, acceptRange{mediaType, quality, i})
+	}
+
+	return ranges
+}
+
+// acceptMatch finds the most specific media range for media type
+func acceptMatch(ranges []acceptRange, mediaType string) (acceptRange, bool) {
+	var match acceptRange
+	found := false
+
+	for _, ar := range ranges {
+		if ar.matches(mediaType) && (!found || ar.specificity() > match.specificity()) {
+			match = ar
+			found = true
+		}
+	}
+
+	return match, found
+}
+
+// NegotiateEncoder picks [Encoder] for request's Accept header
+// or returns [ResponseError] with 406 status
+func NegotiateEncoder(r *http.Request) (string, Encoder, error) {
+	encoders.RLock()
+	defer encoders.RUnlock()
+
+	header := ""
+	if r != nil {
+		header = strings.Join(r.Header.Values("Accept"), ",")
+	}
+	if len(strings.TrimSpace(header)) < 1 {
+		return ContentTypeJSON, encoders.items[ContentTypeJSON], nil
+	}
+
+	var best acceptRange
+	bestType := ""
+	ranges := parseAccept(header)
+
+	for _, mediaType := range encoders.types {
+		ar, ok := acceptMatch(ranges, mediaType)
+		if !ok || ar.quality <= 0 {
+			continue
+		}
+
+		if len(bestType) < 1 || ar.quality > best.quality ||
+			(ar.quality == best.quality && ar.index < best.index) {
+			best = ar
+			bestType = mediaType
+		}
+	}
+
+	if len(bestType) > 0 {
+		return bestType, encoders.items[bestType], nil
+	}
+
+	return "", nil, Errorf("unable to produce acceptable response for [%s]", header).
+		Status(http.StatusNotAcceptable).
+		Reason("not_acceptable")
+}
+
+// EncodeJSON encodes data as json
+func EncodeJSON(w io.Writer, data any) error {
+	bt, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bt)
+	return err
+}
+
+// EncodeXML encodes data as xml
+func EncodeXML(w io.Writer, data any) error {
+	bt, err := xml.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(bt)
+	return err
+}
+
+// EncodeText encodes data as plain text
+func EncodeText(w io.Writer, data any) error {
+	var err error
+
+	switch v := data.(type) {
+	case string:
+		_, err = io.WriteString(w, v)
+	case []byte:
+		_, err = w.Write(v)
+	case map[string]any:
+		err = EncodeText(w, JSON(v))
+	case JSON:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			_, err = fmt.Fprintf(w, "%s: %v\n", key, v[key])
+			if err != nil {
+				return err
+			}
+		}
+	default:
+		_, err = fmt.Fprint(w, v)
+	}
+
+	return err
+}
+
+// EncodeForm encodes data as url encoded form
+func EncodeForm(w io.Writer, data any) error {
+	var values JSON
+
+	switch v := data.(type) {
+	case JSON:
+		values = v
+	case map[string]any:
+		values = JSON(v)
+	default:
+		err := StructToStruct(data, &values)
+		if err != nil {
+			return err
+		}
+	}
+
+	form := url.Values{}
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+			form.Set(key, "")
+		case []any:
+			for _, item := range v {
+				form.Add(key, fmt.Sprint(item))
+			}
+		default:
+			form.Set(key, fmt.Sprint(v))
+		}
+	}
+
+	_, err := io.WriteString(w, form.Encode())
+	return err
+}
+
+// encodeXMLValue writes value as xml element
+func encodeXMLValue(e *xml.Encoder, start xml.StartElement, value any) error {
+	switch v := value.(type) {
+	case nil:
+		return e.EncodeElement("", start)
+	case map[string]any:
+		return JSON(v).MarshalXML(e, start)
+	case []any:
+		for _, item := range v {
+			err := encodeXMLValue(e, start, item)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return e.EncodeElement(value, start)
+}
+
+// MarshalXML encodes [JSON] as xml elements in sorted key order
+func (j JSON) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if start.Name.Local == "JSON" {
+		start.Name.Local = "response"
+	}
+
+	err := e.EncodeToken(start)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(j)) {
+		err = encodeXMLValue(e, xml.StartElement{Name: xml.Name{Local: key}}, j[key])
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
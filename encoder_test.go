@@ -0,0 +1,116 @@
+package hndlor_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func CreateEncoderTestRouter() *hndlor.MuxRouter {
+	r := CreateTestRouter()
+	r.Handle("GET /user/{name}", hndlor.New(func(name string) (hndlor.JSON, error) {
+		return hndlor.JSON{
+			"username": name,
+		}, nil
+	}, hndlor.Path[string]("name")))
+	r.Handle("GET /avatar", hndlor.New(func(w http.ResponseWriter) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write([]byte("\x89PNG"))
+	}, hndlor.HTTPResponseWriter()))
+	r.Handle("DELETE /user/{name}", hndlor.New(func(name string) error {
+		return nil
+	}, hndlor.Path[string]("name")))
+
+	return r
+}
+
+func TestNegotiatedResponse(t *testing.T) {
+	r := CreateEncoderTestRouter()
+
+	cases := map[string]string{
+		"application/xml":                       hndlor.ContentTypeXML,
+		"text/html, text/*;q=0.8, */*;q=0.1":    "text/plain; charset=utf-8",
+		"application/json;q=0.5, */*;q=0.9":     hndlor.ContentTypeXML,
+		"application/x-www-form-urlencoded":     hndlor.ContentTypeURLEncoded,
+		"application/json;q=0, application/xml": hndlor.ContentTypeXML,
+	}
+
+	for accept, expected := range cases {
+		res, err := RunTestRequest(r, "GET", "/user/John", func(r *http.Request) {
+			r.Header.Set("Accept", accept)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := res.Result()
+
+		if err := InvalidateTestResultStatus(response, 200); err != nil {
+			t.Error(err)
+		} else if ct := response.Header.Get("Content-Type"); ct != expected {
+			t.Errorf("invalid content type for [%s]; expected %s but got %s", accept, expected, ct)
+		}
+	}
+}
+
+func TestXMLResponse(t *testing.T) {
+	r := CreateEncoderTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/user/John", func(r *http.Request) {
+		r.Header.Set("Accept", "application/xml")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bt, _ := io.ReadAll(res.Result().Body)
+	if !strings.Contains(string(bt), "<response><username>John</username></response>") {
+		t.Errorf("unable to resolve xml response: %s", bt)
+	}
+}
+
+func TestNotAcceptableResponse(t *testing.T) {
+	r := CreateEncoderTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/user/John", func(r *http.Request) {
+		r.Header.Set("Accept", "image/png")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 406)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["reason"] != "not_acceptable" {
+			t.Error("unable to resolve not acceptable error")
+		}
+	}
+}
+
+func TestNegotiationSkippedResponse(t *testing.T) {
+	r := CreateEncoderTestRouter()
+
+	for method, status := range map[string]int{"GET /avatar": 200, "DELETE /user/John": 204} {
+		parts := strings.SplitN(method, " ", 2)
+		res, err := RunTestRequest(r, parts[0], parts[1], func(r *http.Request) {
+			r.Header.Set("Accept", "image/png")
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = InvalidateTestResultStatus(res.Result(), status)
+		if err != nil {
+			t.Errorf("%s: %s", method, err)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package hndlor
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileRule defines function signature for uploaded file validation
+type FileRule func(*multipart.FileHeader) error
+
+// FormFiles reads uploaded files of field from parsed multipart form
+func FormFiles(r *http.Request, field string) []*multipart.FileHeader {
+	if r.MultipartForm != nil && r.MultipartForm.File != nil {
+		return r.MultipartForm.File[field]
+	}
+	return nil
+}
+
+// SniffContentType detects the content type from the
+// uploaded file content ignoring the provided header
+func SniffContentType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	return mediaType, err
+}
+
+// FileMaxSize validates uploaded file doesn't exceed size in bytes
+func FileMaxSize(size int64) FileRule {
+	return func(fh *multipart.FileHeader) error {
+		if fh.Size > size {
+			return Errorf("file [%s] exceeds maximum size of %d bytes", fh.Filename, size).
+				Status(http.StatusUnprocessableEntity).
+				Reason("file_too_large")
+		}
+		return nil
+	}
+}
+
+// FileTypes validates sniffed content type of uploaded file
+// against allowed media types; supports wildcards like image/*
+func FileTypes(types ...string) FileRule {
+	return func(fh *multipart.FileHeader) error {
+		mediaType, err := SniffContentType(fh)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range types {
+			t = strings.ToLower(t)
+			if t == mediaType || (strings.HasSuffix(t, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(t, "*"))) {
+				return nil
+			}
+		}
+
+		return Errorf("file [%s] of type [%s] isn't allowed", fh.Filename, mediaType).
+			Status(http.StatusUnprocessableEntity).
+			Reason("file_type_invalid")
+	}
+}
+
+// FileExts validates extension of uploaded file name
+func FileExts(exts ...string) FileRule {
+	allowed := make([]string, len(exts))
+	for i, ext := range exts {
+		allowed[i] = "." + strings.TrimPrefix(strings.ToLower(ext), ".")
+	}
+
+	return func(fh *multipart.FileHeader) error {
+		ext := strings.ToLower(filepath.Ext(fh.Filename))
+		if !slices.Contains(allowed, ext) {
+			return Errorf("file [%s] with extension [%s] isn't allowed", fh.Filename, ext).
+				Status(http.StatusUnprocessableEntity).
+				Reason("file_ext_invalid")
+		}
+		return nil
+	}
+}
+
+// ValidateFile builds validator for [File] resolver from rules
+func ValidateFile(rules ...FileRule) func(*http.Request, *multipart.FileHeader) error {
+	return func(_ *http.Request, fh *multipart.FileHeader) error {
+		for _, rule := range rules {
+			err := rule(fh)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ValidateFiles builds validator for [Files] resolver from rules
+func ValidateFiles(rules ...FileRule) func(*http.Request, []*multipart.FileHeader) error {
+	validate := ValidateFile(rules...)
+
+	return func(r *http.Request, fhs []*multipart.FileHeader) error {
+		for _, fh := range fhs {
+			err := validate(r, fh)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// File defines value resolver for uploaded file
+func File(field string) *Value[*multipart.FileHeader] {
+	return NewValue[*multipart.FileHeader](field, ValueSourceFile)
+}
+
+// Files defines value resolver for all uploaded files of field
+func Files(field string) *Value[[]*multipart.FileHeader] {
+	return NewValue[[]*multipart.FileHeader](field, ValueSourceFile)
+}
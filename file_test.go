@@ -0,0 +1,207 @@
+package hndlor_test
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func CreateFileTestRouter() *hndlor.MuxRouter {
+	r := CreateTestRouter()
+	r.Handle("POST /avatar", hndlor.New(func(fh *multipart.FileHeader) (hndlor.JSON, error) {
+		return hndlor.JSON{
+			"name": fh.Filename,
+			"size": fh.Size,
+		}, nil
+	}, hndlor.File("avatar").Validate(hndlor.ValidateFile(
+		hndlor.FileMaxSize(1024),
+		hndlor.FileTypes("image/*"),
+		hndlor.FileExts("png", "jpg"),
+	))))
+	r.Handle("POST /docs", hndlor.New(func(fhs []*multipart.FileHeader) (hndlor.JSON, error) {
+		return hndlor.JSON{
+			"count": len(fhs),
+		}, nil
+	}, hndlor.Files("docs").Validate(hndlor.ValidateFiles(
+		hndlor.FileTypes("application/pdf"),
+	))))
+
+	return r
+}
+
+func RunTestUploadRequest(r http.Handler, path string, field string, files map[string][]byte) (*http.Response, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, content := range files {
+		fw, err := mw.CreateFormFile(field, name)
+		if err != nil {
+			return nil, err
+		}
+		_, _ = fw.Write(content)
+	}
+	_ = mw.Close()
+
+	res, err := RunTestRequestBody(r, "POST", path, &body, func(req *http.Request) {
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.Result(), nil
+}
+
+func TestFileUpload(t *testing.T) {
+	r := CreateFileTestRouter()
+	png := []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+	response, err := RunTestUploadRequest(r, "/avatar", "avatar", map[string][]byte{"me.png": png})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["name"] != "me.png" {
+			t.Error("unable to resolve uploaded file")
+		}
+	}
+}
+
+func TestFileUploadInvalid(t *testing.T) {
+	r := CreateFileTestRouter()
+
+	response, err := RunTestUploadRequest(r, "/avatar", "avatar", map[string][]byte{"me.png": []byte("plain text")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InvalidateTestResultStatus(response, 422)
+	if err != nil {
+		t.Error(err)
+	}
+
+	response, err = RunTestUploadRequest(r, "/docs", "docs", map[string][]byte{
+		"a.pdf": []byte("%PDF-1.4 a"),
+		"b.pdf": []byte("%PDF-1.4 b"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["count"] != float64(2) {
+			t.Error("unable to resolve uploaded files")
+		}
+	}
+}
+
+func CreateUploadLimitRouter(config *hndlor.BodyConfig, spooled *int) *hndlor.MuxRouter {
+	r := hndlor.Router().Use(hndlor.PrepareMux(config))
+	r.Handle("POST /docs", hndlor.New(func(fhs []*multipart.FileHeader) (hndlor.JSON, error) {
+		entries, err := os.ReadDir(os.TempDir())
+		if err != nil {
+			return nil, err
+		}
+		*spooled = len(entries)
+
+		return hndlor.JSON{
+			"count": len(fhs),
+		}, nil
+	}, hndlor.Files("docs")))
+
+	return r
+}
+
+func RunTestUploadServer(t *testing.T, r http.Handler, files map[string][]byte) *http.Response {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, content := range files {
+		fw, err := mw.CreateFormFile("docs", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = fw.Write(content)
+	}
+	_ = mw.Close()
+
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	res, err := http.Post(srv.URL+"/docs", mw.FormDataContentType(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+
+	return res
+}
+
+func AssertTempDirEmpty(t *testing.T) {
+	entries, err := os.ReadDir(os.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	} else if len(entries) > 0 {
+		t.Errorf("temp files left behind: %d", len(entries))
+	}
+}
+
+func TestUploadMaxMemory(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	file := bytes.Repeat([]byte("a"), 64<<10)
+
+	var spooled int
+	res := RunTestUploadServer(t, CreateUploadLimitRouter(&hndlor.BodyConfig{MaxMemory: 1 << 20}, &spooled), map[string][]byte{"a.txt": file})
+	if err := InvalidateTestResultStatus(res, 200); err != nil {
+		t.Error(err)
+	} else if spooled != 0 {
+		t.Errorf("file within memory limit should not be spooled to disk: %d", spooled)
+	}
+
+	res = RunTestUploadServer(t, CreateUploadLimitRouter(&hndlor.BodyConfig{MaxMemory: 1024}, &spooled), map[string][]byte{"a.txt": file})
+	if err := InvalidateTestResultStatus(res, 200); err != nil {
+		t.Error(err)
+	} else if spooled != 1 {
+		t.Errorf("file beyond memory limit should be spooled to disk: %d", spooled)
+	}
+
+	AssertTempDirEmpty(t)
+}
+
+func TestUploadMaxFiles(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	file := bytes.Repeat([]byte("a"), 8<<10)
+	config := &hndlor.BodyConfig{MaxMemory: 1024, MaxFiles: 2}
+
+	var spooled int
+	res := RunTestUploadServer(t, CreateUploadLimitRouter(config, &spooled), map[string][]byte{"a.txt": file, "b.txt": file})
+	if err := InvalidateTestResultStatus(res, 200); err != nil {
+		t.Error(err)
+	}
+
+	res = RunTestUploadServer(t, CreateUploadLimitRouter(config, &spooled), map[string][]byte{"a.txt": file, "b.txt": file, "c.txt": file})
+	if err := InvalidateTestResultStatus(res, 413); err != nil {
+		t.Error(err)
+	}
+
+	AssertTempDirEmpty(t)
+}
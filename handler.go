@@ -1,132 +1,223 @@
-package hndlor
-
-import (
-	"net/http"
-	"reflect"
-)
-
-type ValueFailHandler func(ValueResolver, error) error
-
-// Handler defines struct for callback
-type Handler struct {
-	callback   any
-	Err        error
-	zeroOutput bool
-	values     []ValueResolver
-	valueFail  ValueFailHandler
-}
-
-// OnFail defines callback when value resolve fails
-func (h *Handler) OnFail(cb ValueFailHandler) *Handler {
-	h.valueFail = cb
-	return h
-}
-
-// Invalidate verifies the provided function with requested values
-func (h *Handler) Invalidate() error {
-	if h.Err == nil {
-		vLen := len(h.values)
-		tp := reflect.TypeOf(h.callback)
-
-		if tp.Kind() != reflect.Func {
-			h.Err = Errorf("invalid handler type; expected func got [ %s ]", tp).Server()
-		} else {
-			ins := make([]reflect.Type, vLen)
-			for i := range vLen {
-				ins[i] = h.values[i].Type()
-			}
-			outs := []reflect.Type{
-				reflect.TypeOf(make(JSON)),
-				reflect.TypeOf((*error)(nil)).Elem(),
-			}
-			ep := reflect.FuncOf(ins, outs, false)
-			ep2 := reflect.FuncOf(ins, nil, false)
-
-			if tp != ep {
-				if tp != ep2 {
-					h.Err = Errorf("invalid handler function; expected [ %s ] got [ %s ]", ep, tp).Server()
-				} else {
-					h.zeroOutput = true
-				}
-			}
-		}
-	}
-
-	return h.Err
-}
-
-// Values resolves the dynamic handler values
-func (h *Handler) Values(w http.ResponseWriter, r *http.Request) ([]reflect.Value, error) {
-	vLen := len(h.values)
-	values := make([]reflect.Value, vLen)
-
-	for i := range vLen {
-		value := h.values[i]
-		val, err := value.Resolve(w, r)
-
-		if err != nil {
-			if h.valueFail != nil {
-				err = h.valueFail(value, err)
-			}
-
-			if err != nil {
-				return nil, err
-			}
-			values[i] = reflect.ValueOf(value.Default())
-		} else {
-			values[i] = reflect.ValueOf(val)
-		}
-	}
-
-	return values, nil
-}
-
-func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if verr := h.Invalidate(); verr != nil {
-		_ = WriteError(w, verr)
-		return
-	}
-
-	values, err := h.Values(w, r)
-	if err != nil {
-		_ = WriteError(w, err)
-		return
-	}
-
-	vt := reflect.ValueOf(h.callback)
-	response := vt.Call(values)
-
-	if !h.zeroOutput {
-		data, rerr := response[0].Interface(), response[1].Interface()
-
-		if rerr != nil {
-			_ = WriteError(w, rerr.(error))
-			return
-		}
-
-		_ = WriteData(w, data.(JSON))
-	}
-}
-
-// New creates [Handler] for handling response and
-// panics on invalid func signature
-//
-// Example: reads query string 'name' and passes as func argument
-//
-//	mux.Handle("GET /hello", hndlor.New(func(name string) (hndlor.JSON, error) {
-//		return hndlor.JSON{
-//			"hello": name,
-//		}, nil
-//	}, hndlor.Get[string]("name")))
-func New(cb any, values ...ValueResolver) *Handler {
-	h := &Handler{
-		callback: cb,
-		values:   values,
-	}
-
-	if err := h.Invalidate(); err != nil {
-		panic(err)
-	}
-
-	return h
-}
+package hndlor
+
+import (
+	"net/http"
+	"reflect"
+)
+
+type ValueFailHandler func(ValueResolver, error) error
+
+// Handler defines struct for callback
+type Handler struct {
+	callback   any
+	invoke     func([]any) (any, error)
+	Err        error
+	zeroOutput bool
+	noContent  bool
+	aggregate  bool
+	values     []ValueResolver
+	valueFail  ValueFailHandler
+}
+
+// OnFail defines callback when value resolve fails
+func (h *Handler) OnFail(cb ValueFailHandler) *Handler {
+	h.valueFail = cb
+	return h
+}
+
+// Aggregate resolves every value before failing and responds with all
+// failures as single [ValidationError]; [ValueFailHandler] can still
+// rewrite or suppress each individual error
+func (h *Handler) Aggregate() *Handler {
+	h.aggregate = true
+	return h
+}
+
+// Invalidate verifies the provided function with requested values
+//
+// Supported signatures are func(...) (T, error), func(...) error and func(...)
+func (h *Handler) Invalidate() error {
+	if h.Err == nil && h.invoke == nil {
+		vLen := len(h.values)
+		tp := reflect.TypeOf(h.callback)
+
+		if tp == nil || tp.Kind() != reflect.Func {
+			h.Err = Errorf("invalid handler type; expected func got [ %s ]", tp).Server()
+		} else {
+			ins := make([]reflect.Type, vLen)
+			for i := range vLen {
+				ins[i] = h.values[i].Type()
+			}
+			ep := reflect.FuncOf(ins, nil, false)
+
+			if !matchHandlerInputs(tp, ins) {
+				h.Err = Errorf("invalid handler function; expected [ %s ] got [ %s ]", ep, tp).Server()
+			} else {
+				errType := reflect.TypeOf((*error)(nil)).Elem()
+
+				switch {
+				case tp.NumOut() == 0:
+					h.zeroOutput = true
+				case tp.NumOut() == 1 && tp.Out(0) == errType:
+					h.noContent = true
+				case tp.NumOut() == 2 && tp.Out(1) == errType:
+				default:
+					h.Err = Errorf("invalid handler function; expected [ %s (T, error) ] got [ %s ]", ep, tp).Server()
+				}
+			}
+		}
+	}
+
+	return h.Err
+}
+
+// matchHandlerInputs checks if func arguments matches resolved value types
+func matchHandlerInputs(tp reflect.Type, ins []reflect.Type) bool {
+	if tp.IsVariadic() || tp.NumIn() != len(ins) {
+		return false
+	}
+
+	for i, in := range ins {
+		if tp.In(i) != in {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolve evaluates the dynamic handler values in order
+func (h *Handler) resolve(w http.ResponseWriter, r *http.Request) ([]any, error) {
+	if h.aggregate {
+		return resolveAll(w, r, h.values, h.valueFail)
+	}
+
+	vLen := len(h.values)
+	values := make([]any, vLen)
+
+	for i := range vLen {
+		value := h.values[i]
+		val, err := value.Resolve(w, r)
+
+		if err != nil {
+			if h.valueFail != nil {
+				err = h.valueFail(value, err)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value.DefaultValue(r)
+		} else {
+			values[i] = val
+		}
+	}
+
+	return values, nil
+}
+
+// Values resolves the dynamic handler values
+func (h *Handler) Values(w http.ResponseWriter, r *http.Request) ([]reflect.Value, error) {
+	args, err := h.resolve(w, r)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			values[i] = reflect.Zero(h.values[i].Type())
+		} else {
+			values[i] = reflect.ValueOf(arg)
+		}
+	}
+
+	return values, nil
+}
+
+// call executes the callback with resolved values
+func (h *Handler) call(w http.ResponseWriter, r *http.Request) (any, bool, error) {
+	if h.invoke != nil {
+		args, err := h.resolve(w, r)
+		if err != nil {
+			return nil, false, err
+		}
+
+		data, err := h.invoke(args)
+		return data, true, err
+	}
+
+	values, err := h.Values(w, r)
+	if err != nil {
+		return nil, false, err
+	}
+
+	response := reflect.ValueOf(h.callback).Call(values)
+	if h.zeroOutput {
+		return nil, false, nil
+	}
+
+	rerr := response[len(response)-1].Interface()
+	if rerr != nil {
+		return nil, false, rerr.(error)
+	}
+
+	if h.noContent {
+		return nil, false, nil
+	}
+
+	return response[0].Interface(), true, nil
+}
+
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if verr := h.Invalidate(); verr != nil {
+		_ = WriteError(w, verr, r)
+		return
+	}
+
+	data, ok, err := h.call(w, r)
+	if err != nil {
+		_ = WriteError(w, err, r)
+		return
+	}
+
+	if ok {
+		if res, isRes := data.(AsWritableResponse); isRes {
+			err = res.WriteResponse(w, r)
+		} else {
+			err = WriteData(w, data, r)
+		}
+
+		if err != nil {
+			_ = WriteError(w, err, r)
+		}
+	} else if h.noContent {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// New creates [Handler] for handling response and
+// panics on invalid func signature
+//
+// Callback may return any serializable value or [AsWritableResponse]
+// with error, only error to respond with 204 No Content or nothing at all
+//
+// Example: reads query string 'name' and passes as func argument
+//
+//	mux.Handle("GET /hello", hndlor.New(func(name string) (hndlor.JSON, error) {
+//		return hndlor.JSON{
+//			"hello": name,
+//		}, nil
+//	}, hndlor.Get[string]("name")))
+func New(cb any, values ...ValueResolver) *Handler {
+	h := &Handler{
+		callback: cb,
+		values:   values,
+	}
+
+	if err := h.Invalidate(); err != nil {
+		panic(err)
+	}
+
+	return h
+}
@@ -0,0 +1,123 @@
+package hndlor
+
+// arg casts resolved handler value to expected type
+func arg[T any](args []any, i int) T {
+	v, _ := args[i].(T)
+	return v
+}
+
+// newTyped creates [Handler] with type-safe invoker
+func newTyped(invoke func([]any) (any, error), values ...ValueResolver) *Handler {
+	return &Handler{
+		invoke: invoke,
+		values: values,
+	}
+}
+
+// New1 creates type-safe [Handler] with 1 resolved value
+//
+// Example: reads query string 'name' and passes as func argument
+//
+//	mux.Handle("GET /hello", hndlor.New1(func(name string) (hndlor.JSON, error) {
+//		return hndlor.JSON{
+//			"hello": name,
+//		}, nil
+//	}, hndlor.Get[string]("name")))
+func New1[A, R any](cb func(A) (R, error), va *Value[A]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+		)
+	}, va)
+}
+
+// New2 creates type-safe [Handler] with 2 resolved values
+func New2[A, B, R any](cb func(A, B) (R, error), va *Value[A], vb *Value[B]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+			arg[B](args, 1),
+		)
+	}, va, vb)
+}
+
+// New3 creates type-safe [Handler] with 3 resolved values
+func New3[A, B, C, R any](cb func(A, B, C) (R, error), va *Value[A], vb *Value[B], vc *Value[C]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+			arg[B](args, 1),
+			arg[C](args, 2),
+		)
+	}, va, vb, vc)
+}
+
+// New4 creates type-safe [Handler] with 4 resolved values
+func New4[A, B, C, D, R any](cb func(A, B, C, D) (R, error), va *Value[A], vb *Value[B], vc *Value[C], vd *Value[D]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+			arg[B](args, 1),
+			arg[C](args, 2),
+			arg[D](args, 3),
+		)
+	}, va, vb, vc, vd)
+}
+
+// New5 creates type-safe [Handler] with 5 resolved values
+func New5[A, B, C, D, E, R any](cb func(A, B, C, D, E) (R, error), va *Value[A], vb *Value[B], vc *Value[C], vd *Value[D], ve *Value[E]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+			arg[B](args, 1),
+			arg[C](args, 2),
+			arg[D](args, 3),
+			arg[E](args, 4),
+		)
+	}, va, vb, vc, vd, ve)
+}
+
+// New6 creates type-safe [Handler] with 6 resolved values
+func New6[A, B, C, D, E, F, R any](cb func(A, B, C, D, E, F) (R, error), va *Value[A], vb *Value[B], vc *Value[C], vd *Value[D], ve *Value[E], vf *Value[F]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+			arg[B](args, 1),
+			arg[C](args, 2),
+			arg[D](args, 3),
+			arg[E](args, 4),
+			arg[F](args, 5),
+		)
+	}, va, vb, vc, vd, ve, vf)
+}
+
+// New7 creates type-safe [Handler] with 7 resolved values
+func New7[A, B, C, D, E, F, G, R any](cb func(A, B, C, D, E, F, G) (R, error), va *Value[A], vb *Value[B], vc *Value[C], vd *Value[D], ve *Value[E], vf *Value[F], vg *Value[G]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+			arg[B](args, 1),
+			arg[C](args, 2),
+			arg[D](args, 3),
+			arg[E](args, 4),
+			arg[F](args, 5),
+			arg[G](args, 6),
+		)
+	}, va, vb, vc, vd, ve, vf, vg)
+}
+
+// New8 creates type-safe [Handler] with 8 resolved values
+func New8[A, B, C, D, E, F, G, H, R any](cb func(A, B, C, D, E, F, G, H) (R, error), va *Value[A], vb *Value[B], vc *Value[C], vd *Value[D], ve *Value[E], vf *Value[F], vg *Value[G], vh *Value[H]) *Handler {
+	return newTyped(func(args []any) (any, error) {
+		return cb(
+			arg[A](args, 0),
+			arg[B](args, 1),
+			arg[C](args, 2),
+			arg[D](args, 3),
+			arg[E](args, 4),
+			arg[F](args, 5),
+			arg[G](args, 6),
+			arg[H](args, 7),
+		)
+	}, va, vb, vc, vd, ve, vf, vg, vh)
+}
@@ -0,0 +1,447 @@
+package hndlor
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwtEncoding used to encode token segments
+var jwtEncoding = base64.RawURLEncoding
+
+// errInvalidToken creates error for malformed or unverified token
+func errInvalidToken() *ResponseError {
+	return Error("invalid token").
+		Status(http.StatusUnauthorized).
+		Reason("token_invalid")
+}
+
+// JWTConfig defines options for [JWT] middleware
+type JWTConfig struct {
+
+	// default verification key when token has no kid or
+	// kid is not found in added keys; []byte for HS256, *rsa.PublicKey for RS256,
+	// *ecdsa.PublicKey for ES256 and ed25519.PublicKey for EdDSA
+	Key any
+
+	// expected iss claim
+	Issuer string
+
+	// expected value in aud claim
+	Audience string
+
+	// allowed clock skew for exp and nbf
+	Skew time.Duration
+
+	// realm on WWW-Authenticate header
+	Realm string
+
+	// keys by kid
+	keys map[string]any
+	mu   sync.RWMutex
+}
+
+// AddKey adds verification key for kid
+func (c *JWTConfig) AddKey(kid string, key any) *JWTConfig {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.keys == nil {
+		c.keys = make(map[string]any)
+	}
+	c.keys[kid] = key
+	return c
+}
+
+// LoadJWKS adds verification keys from JWKS document
+func (c *JWTConfig) LoadJWKS(data []byte) error {
+	keys, err := ParseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	for kid, key := range keys {
+		c.AddKey(kid, key)
+	}
+	return nil
+}
+
+// LoadJWKSFile adds verification keys from JWKS document file
+func (c *JWTConfig) LoadJWKSFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return c.LoadJWKS(data)
+}
+
+// keyFor finds verification key for kid falling back to default key
+func (c *JWTConfig) keyFor(kid string) (any, bool) {
+	if len(kid) > 0 {
+		c.mu.RLock()
+		key, ok := c.keys[kid]
+		c.mu.RUnlock()
+
+		if ok {
+			return key, true
+		}
+	}
+
+	return c.Key, c.Key != nil
+}
+
+// jwk defines supported fields of json web key
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// ParseJWKS parses JWKS document to verification keys by kid
+func ParseJWKS(data []byte) (map[string]any, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	err := json.Unmarshal(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]any)
+	for _, k := range doc.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid jwk [%s]: %w", k.Kid, err)
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+// publicKey decodes json web key to verification key
+func (k jwk) publicKey() (any, error) {
+	decode := func(vals ...string) ([][]byte, error) {
+		res := make([][]byte, len(vals))
+		for i, v := range vals {
+			b, err := jwtEncoding.DecodeString(v)
+			if err != nil || len(b) < 1 {
+				return nil, fmt.Errorf("invalid key parameter")
+			}
+			res[i] = b
+		}
+		return res, nil
+	}
+
+	switch {
+	case k.Kty == "RSA":
+		p, err := decode(k.N, k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		e := new(big.Int).SetBytes(p[1])
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(p[0]), E: int(e.Int64())}, nil
+	case k.Kty == "EC" && k.Crv == "P-256":
+		p, err := decode(k.X, k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(p[0]) != 32 || len(p[1]) != 32 {
+			return nil, fmt.Errorf("invalid ec point")
+		}
+
+		// validate point is on curve
+		_, err = ecdh.P256().NewPublicKey(slices.Concat([]byte{4}, p[0], p[1]))
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(p[0]),
+			Y:     new(big.Int).SetBytes(p[1]),
+		}, nil
+	case k.Kty == "OKP" && k.Crv == "Ed25519":
+		p, err := decode(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(p[0]) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid ed25519 key")
+		}
+		return ed25519.PublicKey(p[0]), nil
+	case k.Kty == "oct":
+		p, err := decode(k.K)
+		if err != nil {
+			return nil, err
+		}
+		return p[0], nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %s", k.Kty)
+}
+
+// jwtAlgorithm returns algorithm for key type to prevent algorithm confusion
+func jwtAlgorithm(key any) string {
+	switch k := key.(type) {
+	case []byte:
+		return "HS256"
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		return "RS256"
+	case ed25519.PublicKey, ed25519.PrivateKey:
+		return "EdDSA"
+	case *ecdsa.PrivateKey:
+		return jwtAlgorithm(&k.PublicKey)
+	case *ecdsa.PublicKey:
+		if k.Curve == elliptic.P256() {
+			return "ES256"
+		}
+	}
+	return ""
+}
+
+// SignJWT creates signed token of claims using key for its algorithm;
+// []byte for HS256, *rsa.PrivateKey, *ecdsa.PrivateKey (P-256) or ed25519.PrivateKey
+func SignJWT(claims any, key any, kid string) (string, error) {
+	alg := jwtAlgorithm(key)
+	if len(alg) < 1 {
+		return "", fmt.Errorf("unsupported signing key %T", key)
+	}
+
+	header := JSON{"alg": alg, "typ": "JWT"}
+	if len(kid) > 0 {
+		header["kid"] = kid
+	}
+
+	hb, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	cb, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	input := jwtEncoding.EncodeToString(hb) + "." + jwtEncoding.EncodeToString(cb)
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+	case *ecdsa.PrivateKey:
+		var r, s *big.Int
+		r, s, err = ecdsa.Sign(rand.Reader, k, digest[:])
+		if err == nil {
+			sig = make([]byte, 64)
+			r.FillBytes(sig[:32])
+			s.FillBytes(sig[32:])
+		}
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(input))
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return input + "." + jwtEncoding.EncodeToString(sig), nil
+}
+
+// verifyJWTSignature verifies signature of signing input using key
+func verifyJWTSignature(key any, input, sig []byte) bool {
+	digest := sha256.Sum256(input)
+
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write(input)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case *ecdsa.PublicKey:
+		if len(sig) != 64 {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(k, digest[:], r, s)
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, input, sig)
+	}
+	return false
+}
+
+// claimTime reads numeric date claim
+func claimTime(claims JSON, key string) (time.Time, bool, error) {
+	v, ok := claims[key]
+	if !ok {
+		return time.Time{}, false, nil
+	}
+
+	n, ok := v.(json.Number)
+	if !ok {
+		return time.Time{}, false, errInvalidToken()
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false, errInvalidToken()
+	}
+
+	sec, frac := math.Modf(f)
+	return time.Unix(int64(sec), int64(frac*float64(time.Second))), true, nil
+}
+
+// hasAudience checks if aud claim contains audience
+func hasAudience(claims JSON, audience string) bool {
+	switch aud := claims["aud"].(type) {
+	case string:
+		return aud == audience
+	case []any:
+		return slices.Contains(aud, any(audience))
+	}
+	return false
+}
+
+// VerifyJWT verifies token signature and registered claims
+func VerifyJWT(token string, config *JWTConfig) (JSON, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errInvalidToken()
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	hb, err := jwtEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(hb, &header) != nil {
+		return nil, errInvalidToken()
+	}
+
+	key, ok := config.keyFor(header.Kid)
+	if !ok || jwtAlgorithm(key) != header.Alg {
+		return nil, errInvalidToken()
+	}
+
+	sig, err := jwtEncoding.DecodeString(parts[2])
+	if err != nil || !verifyJWTSignature(key, []byte(parts[0]+"."+parts[1]), sig) {
+		return nil, errInvalidToken()
+	}
+
+	cb, err := jwtEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errInvalidToken()
+	}
+
+	var claims JSON
+	dec := json.NewDecoder(bytes.NewReader(cb))
+	dec.UseNumber()
+	if dec.Decode(&claims) != nil || claims == nil {
+		return nil, errInvalidToken()
+	}
+
+	now := time.Now()
+	exp, ok, err := claimTime(claims, "exp")
+	if err != nil {
+		return nil, err
+	} else if ok && now.After(exp.Add(config.Skew)) {
+		return nil, Error("token expired").
+			Status(http.StatusUnauthorized).
+			Reason("token_expired")
+	}
+
+	nbf, ok, err := claimTime(claims, "nbf")
+	if err != nil {
+		return nil, err
+	} else if ok && now.Add(config.Skew).Before(nbf) {
+		return nil, Error("token not yet valid").
+			Status(http.StatusUnauthorized).
+			Reason("token_not_valid_yet")
+	}
+
+	if len(config.Issuer) > 0 && claims["iss"] != config.Issuer {
+		return nil, errInvalidToken()
+	}
+	if len(config.Audience) > 0 && !hasAudience(claims, config.Audience) {
+		return nil, errInvalidToken()
+	}
+
+	return claims, nil
+}
+
+// GetClaims retrieves verified token claims saved by [JWT] middleware
+func GetClaims(r *http.Request) JSON {
+	claims, _ := r.Context().Value(ContextValueClaims).(JSON)
+	return claims
+}
+
+// JWT middleware builds handler to verify bearer token and save its claims
+// on request with sub claim as principal
+func JWT(config *JWTConfig) NextHandler {
+	challenge := fmt.Sprintf("Bearer realm=%q", config.Realm)
+
+	return MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		token, ok := BearerToken(r)
+		if !ok {
+			return authError(w, challenge, errUnauthorized())
+		}
+
+		claims, err := VerifyJWT(token, config)
+		if err != nil {
+			return authError(w, challenge+`, error="invalid_token"`, err)
+		}
+
+		r = Patch(r, ContextValueClaims, claims)
+		next.ServeHTTP(w, PatchValue(r, PrincipalKey, claims["sub"]))
+		return nil
+	})
+}
+
+// Claims defines value resolver of token claims saved by [JWT] middleware
+func Claims[T any]() *Value[T] {
+	return Reader(func(_ http.ResponseWriter, r *http.Request) (T, error) {
+		var data T
+		claims := GetClaims(r)
+		if claims == nil {
+			return data, errUnauthorized()
+		}
+
+		err := StructToStruct(claims, &data)
+		return data, err
+	}).As("claims")
+}
@@ -0,0 +1,162 @@
+package hndlor_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+type TestClaims struct {
+	Subject string `json:"sub"`
+	Role    string `json:"role"`
+	Expires int64  `json:"exp"`
+}
+
+func TestJWTMiddleware(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPub, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	secret := []byte("hs-secret")
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := fmt.Sprintf(`{"keys": [
+		{"kid": "rsa-1", "kty": "RSA", "n": %q, "e": %q},
+		{"kid": "ec-1", "kty": "EC", "crv": "P-256", "x": %q, "y": %q},
+		{"kid": "ed-1", "kty": "OKP", "crv": "Ed25519", "x": %q}
+	]}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.FillBytes(make([]byte, 32))), b64(ecKey.Y.FillBytes(make([]byte, 32))),
+		b64(edPub),
+	)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	err := os.WriteFile(path, []byte(jwks), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &hndlor.JWTConfig{
+		Key:      secret,
+		Issuer:   "auth.example.com",
+		Audience: "api",
+		Skew:     30 * time.Second,
+	}
+	err = config.LoadJWKSFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := hndlor.Router().Use(hndlor.JWT(config))
+	r.Handle("GET /me", hndlor.New(func(claims TestClaims, sub string) (hndlor.JSON, error) {
+		return hndlor.JSON{"sub": claims.Subject, "role": claims.Role, "principal": sub}, nil
+	}, hndlor.Claims[TestClaims](), hndlor.Context[string](hndlor.PrincipalKey)))
+
+	now := time.Now().Unix()
+	valid := hndlor.JSON{"sub": "user-1", "role": "admin", "iss": "auth.example.com", "aud": []string{"web", "api"}, "exp": now + 60}
+	with := func(changes hndlor.JSON) hndlor.JSON {
+		claims := hndlor.JSON{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		for k, v := range changes {
+			claims[k] = v
+		}
+		return claims
+	}
+	sign := func(claims hndlor.JSON, key any, kid string) string {
+		token, err := hndlor.SignJWT(claims, key, kid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	cases := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"hs256", sign(valid, secret, ""), 200},
+		{"rs256", sign(valid, rsaKey, "rsa-1"), 200},
+		{"es256", sign(valid, ecKey, "ec-1"), 200},
+		{"eddsa", sign(valid, edKey, "ed-1"), 200},
+		{"skew", sign(with(hndlor.JSON{"exp": now - 10}), secret, ""), 200},
+		{"far future", sign(with(hndlor.JSON{"exp": 1e11}), secret, ""), 200},
+		{"expired", sign(with(hndlor.JSON{"exp": now - 60}), secret, ""), 401},
+		{"not before", sign(with(hndlor.JSON{"nbf": now + 60}), secret, ""), 401},
+		{"issuer", sign(with(hndlor.JSON{"iss": "other"}), secret, ""), 401},
+		{"audience", sign(with(hndlor.JSON{"aud": "web"}), secret, ""), 401},
+		{"unknown kid", sign(valid, edKey, "ed-2"), 401},
+		{"default key kid", sign(valid, secret, "hs-1"), 200},
+		{"wrong key", sign(valid, []byte("other"), ""), 401},
+		{"alg confusion", sign(valid, rsaKey.N.Bytes(), "rsa-1"), 401},
+		{"malformed", "a.b", 401},
+	}
+
+	for _, c := range cases {
+		res, err := RunTestRequest(r, "GET", "/me", func(r *http.Request) {
+			r.Header.Set("Authorization", "Bearer "+c.token)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := res.Result()
+
+		err = InvalidateTestResultStatus(response, c.status)
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+
+		if c.status == 401 {
+			if !strings.Contains(response.Header.Get("WWW-Authenticate"), "invalid_token") {
+				t.Errorf("%s: missing challenge", c.name)
+			}
+			continue
+		}
+
+		var data hndlor.JSON
+		err = RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["sub"] != "user-1" || data["role"] != "admin" || data["principal"] != "user-1" {
+			t.Errorf("%s: invalid claims %v", c.name, data)
+		}
+	}
+
+	res, err := RunTestRequest(r, "GET", "/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = InvalidateTestResultStatus(res.Result(), 401)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJWTErrorsNotShared(t *testing.T) {
+	config := &hndlor.JWTConfig{Key: []byte("hs-secret")}
+
+	_, err := hndlor.VerifyJWT("a.b", config)
+	if re, ok := err.(*hndlor.ResponseError); ok {
+		re.Status(http.StatusTeapot).Reason("decorated")
+	}
+
+	_, err = hndlor.VerifyJWT("a.b", config)
+	if re, ok := err.(*hndlor.ResponseError); !ok || re.ResponseStatus() != http.StatusUnauthorized {
+		t.Errorf("invalid token error should be fresh: %v", err)
+	}
+}
@@ -1,68 +1,243 @@
-package hndlor_test
-
-import (
-	"net/http"
-	"testing"
-
-	"github.com/OpenRunic/hndlor"
-)
-
-func CreateMiddlewareTestRouter() *hndlor.MuxRouter {
-	r := CreateTestRouter()
-	r.Use(hndlor.MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
-		token := r.Header.Get("x-api-token")
-		if len(token) < 1 {
-			return hndlor.Error("auth token missing").Status(http.StatusForbidden)
-		}
-
-		next.ServeHTTP(w, hndlor.PatchValue(r, "authToken", token))
-		return nil
-	}))
-	r.Handle("GET /me", hndlor.New(func(token string) (hndlor.JSON, error) {
-		return hndlor.JSON{
-			"token": token,
-		}, nil
-	}, hndlor.Context[string]("authToken")))
-
-	return r
-}
-
-func TestRouteMiddleware(t *testing.T) {
-	r := CreateMiddlewareTestRouter()
-
-	res, err := RunTestRequest(r, "GET", "/me", func(r *http.Request) {
-		r.Header.Set("x-api-token", "111")
-	})
-	if err != nil {
-		t.Fatal(err)
-	}
-	response := res.Result()
-
-	err = InvalidateTestResultStatus(response, 200)
-	if err != nil {
-		t.Error(err)
-	} else {
-		var data hndlor.JSON
-		err := RunTestResultDecode(response, &data)
-		if err != nil {
-			t.Error(err)
-		} else if data["token"] != "111" {
-			t.Error("unable to resolve valid token on response data")
-		}
-	}
-}
-
-func TestRouteMiddlewareFail(t *testing.T) {
-	r := CreateMiddlewareTestRouter()
-
-	res, err := RunTestRequest(r, "GET", "/me")
-	if err != nil {
-		t.Fatal(err)
-	}
-	response := res.Result()
-
-	err = InvalidateTestResultStatus(response, 403)
-	if err != nil {
-		t.Error(err)
-	}
-}
+package hndlor_test
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func CreateMiddlewareTestRouter() *hndlor.MuxRouter {
+	r := CreateTestRouter()
+	r.Use(hndlor.MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		token := r.Header.Get("x-api-token")
+		if len(token) < 1 {
+			return hndlor.Error("auth token missing").Status(http.StatusForbidden)
+		}
+
+		next.ServeHTTP(w, hndlor.PatchValue(r, "authToken", token))
+		return nil
+	}))
+	r.Handle("GET /me", hndlor.New(func(token string) (hndlor.JSON, error) {
+		return hndlor.JSON{
+			"token": token,
+		}, nil
+	}, hndlor.Context[string]("authToken")))
+
+	return r
+}
+
+func TestRouteMiddleware(t *testing.T) {
+	r := CreateMiddlewareTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/me", func(r *http.Request) {
+		r.Header.Set("x-api-token", "111")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["token"] != "111" {
+			t.Error("unable to resolve valid token on response data")
+		}
+	}
+}
+
+func TestRouteMiddlewareFail(t *testing.T) {
+	r := CreateMiddlewareTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 403)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	var reported *hndlor.ResponseError
+
+	r := hndlor.Router().Use(hndlor.Recover(func(_ *http.Request, err *hndlor.ResponseError) {
+		reported = err
+	}))
+	r.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("broken handler")
+	})
+	r.HandleFunc("GET /abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	res, err := RunTestRequest(r, "GET", "/panic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 500)
+	if err != nil {
+		t.Error(err)
+	}
+
+	var data hndlor.JSON
+	err = RunTestResultDecode(response, &data)
+	if err != nil {
+		t.Error(err)
+	} else if data["error"] != http.StatusText(500) || data["reason"] != "panic" {
+		t.Errorf("invalid recovered response: %v", data)
+	}
+
+	if reported == nil || !strings.Contains(reported.AsJSON()["error"].(string), "broken handler") {
+		t.Errorf("recover hook was not called with panic error: %v", reported)
+	} else {
+		var log strings.Builder
+		reported.Log(&log)
+		if !strings.Contains(log.String(), "middleware_test.go") {
+			t.Errorf("recovered error is missing panic location: %s", log.String())
+		}
+	}
+
+	defer func() {
+		if recover() != http.ErrAbortHandler {
+			t.Error("expected http.ErrAbortHandler to be re-panicked")
+		}
+	}()
+	_, _ = RunTestRequest(r, "GET", "/abort")
+}
+
+func TestRequestIDMiddleware(t *testing.T) {
+	var log strings.Builder
+
+	r := hndlor.Router().Use(hndlor.Logger(&log), hndlor.RequestID())
+	r.HandleFunc("GET /fail", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteError(w, hndlor.Error("not found").Status(http.StatusNotFound), r)
+	})
+
+	res, err := RunTestRequest(r, "GET", "/fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	id := response.Header.Get("X-Request-Id")
+	if len(id) != 36 || id[14] != '4' {
+		t.Errorf("invalid generated request id: %q", id)
+	}
+
+	var data hndlor.JSON
+	err = RunTestResultDecode(response, &data)
+	if err != nil {
+		t.Error(err)
+	} else if data["request_id"] != id {
+		t.Errorf("error response is missing request id: %v", data)
+	}
+
+	if !strings.Contains(log.String(), id) {
+		t.Errorf("log line is missing request id: %s", log.String())
+	}
+
+	r = hndlor.Router().Use(hndlor.RequestID(&hndlor.RequestIDConfig{
+		Header:    "X-Trace-Id",
+		Generator: hndlor.ULID,
+	}))
+	r.HandleFunc("GET /id", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, hndlor.GetRequestID(r))
+	})
+
+	res, err = RunTestRequest(r, "GET", "/id", func(r *http.Request) {
+		r.Header.Set("X-Trace-Id", "trace-123")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Result().Header.Get("X-Trace-Id") != "trace-123" {
+		t.Error("incoming request id was not reused")
+	}
+
+	res, err = RunTestRequest(r, "GET", "/id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := res.Result().Header.Get("X-Trace-Id"); len(id) != 26 || id[:10] > hndlor.ULID()[:10] {
+		t.Errorf("invalid generated ulid: %q", id)
+	}
+}
+
+func TestLoggerWithoutRequestID(t *testing.T) {
+	var log strings.Builder
+
+	r := hndlor.Router().Use(hndlor.Logger(&log))
+	r.HandleFunc("GET /ping", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, "pong")
+	})
+
+	_, err := RunTestRequest(r, "GET", "/ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(log.String(), ")\n") || strings.Contains(log.String(), "ID") {
+		t.Errorf("log line should not include empty request id: %s", log.String())
+	}
+}
+
+func TestErrorLogRequestID(t *testing.T) {
+	var logged strings.Builder
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	var reported *hndlor.ResponseError
+	shared := hndlor.Error("database offline").Server()
+
+	r := hndlor.Router().Use(hndlor.RequestID(), hndlor.Recover(func(_ *http.Request, err *hndlor.ResponseError) {
+		reported = err
+	}))
+	r.HandleFunc("GET /panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("broken handler")
+	})
+	r.HandleFunc("GET /fail", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteError(w, shared, r)
+	})
+
+	res, err := RunTestRequest(r, "GET", "/panic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := res.Result().Header.Get("X-Request-Id")
+
+	var trace strings.Builder
+	if reported != nil {
+		reported.Log(&trace)
+	}
+	if len(id) < 1 || !strings.Contains(trace.String(), "request_id="+id) {
+		t.Errorf("recovered error log is missing request id %q: %s", id, trace.String())
+	}
+
+	res, err = RunTestRequest(r, "GET", "/fail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id = res.Result().Header.Get("X-Request-Id")
+
+	if !strings.Contains(logged.String(), "database offline (request_id="+id+")") {
+		t.Errorf("error log is missing request id %q: %s", id, logged.String())
+	}
+	if _, found := shared.ResponseJSON()["request_id"]; found {
+		t.Error("request id should not be set on shared error")
+	}
+}
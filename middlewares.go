@@ -1,93 +1,283 @@
-package hndlor
-
-import (
-	"fmt"
-	"io"
-	"log/slog"
-	"net/http"
-	"time"
-)
-
-// lResponseWriter is a modified writer with logging info
-type lResponseWriter struct {
-	http.ResponseWriter
-	contentSize uint64
-	statusCode  int
-}
-
-func (w *lResponseWriter) Write(data []byte) (int, error) {
-	size, err := w.ResponseWriter.Write(data)
-	if err == nil {
-		w.contentSize = uint64(size)
-	} else {
-		w.contentSize = 0
-	}
-	return size, err
-}
-
-func (w *lResponseWriter) WriteHeader(code int) {
-	w.statusCode = code
-	w.ResponseWriter.WriteHeader(code)
-}
-
-// Logger middleware builds handler to log every requests received
-func Logger(lw any) NextHandler {
-	var ok bool
-	var target string
-	var writer io.Writer
-	var sLogger *slog.Logger
-
-	sLogger, ok = lw.(*slog.Logger)
-	if ok {
-		target = "slog"
-	} else {
-		writer, ok = lw.(io.Writer)
-		if ok {
-			target = "writer"
-		}
-	}
-
-	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
-		nw := &lResponseWriter{w, 0, http.StatusOK}
-
-		if len(target) > 0 {
-			defer func(st time.Time) {
-				etime := time.Since(st)
-
-				switch target {
-				case "slog":
-					sLogger.Info(
-						"http request",
-						"method", r.Method,
-						"path", r.URL.Path,
-						"time_ms", etime,
-						"status", nw.statusCode,
-						"size", nw.contentSize,
-					)
-				case "writer":
-					fmt.Fprintf(writer, "[%s] %s - (T %s, S %d, L %d)\n",
-						r.Method,
-						r.URL.Path,
-						etime,
-						nw.statusCode,
-						nw.contentSize,
-					)
-				}
-			}(time.Now())
-		}
-
-		next.ServeHTTP(nw, r)
-	})
-}
-
-// PrepareMux middleware parses request to create cache data as needed
-func PrepareMux() NextHandler {
-	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
-		nr, err := PrepareBody(r)
-		if err != nil {
-			_ = WriteError(w, Error(err.Error()).Server().Status(http.StatusUnprocessableEntity))
-		} else {
-			next.ServeHTTP(w, nr)
-		}
-	})
-}
+package hndlor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// lResponseWriter is a modified writer with logging info
+type lResponseWriter struct {
+	http.ResponseWriter
+	contentSize      uint64
+	uncompressedSize uint64
+	statusCode       int
+	requestID        string
+}
+
+// requestIDWriter defines writer that records request id for logging
+type requestIDWriter interface {
+	setRequestID(string)
+}
+
+// uncompressedSizeWriter defines writer that records size before compression
+type uncompressedSizeWriter interface {
+	addUncompressedSize(uint64)
+}
+
+func (w *lResponseWriter) setRequestID(id string) {
+	w.requestID = id
+}
+
+func (w *lResponseWriter) addUncompressedSize(size uint64) {
+	w.uncompressedSize += size
+}
+
+func (w *lResponseWriter) Write(data []byte) (int, error) {
+	size, err := w.ResponseWriter.Write(data)
+	w.contentSize += uint64(size)
+	return size, err
+}
+
+func (w *lResponseWriter) WriteHeader(code int) {
+	w.statusCode = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *lResponseWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *lResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *lResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// findWriter finds writer of type T through wrapped writers
+func findWriter[T any](w http.ResponseWriter) (T, bool) {
+	for {
+		if tw, ok := w.(T); ok {
+			return tw, true
+		}
+
+		uw, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			var zero T
+			return zero, false
+		}
+		w = uw.Unwrap()
+	}
+}
+
+// Logger middleware builds handler to log every requests received
+func Logger(lw any) NextHandler {
+	var ok bool
+	var target string
+	var writer io.Writer
+	var sLogger *slog.Logger
+
+	sLogger, ok = lw.(*slog.Logger)
+	if ok {
+		target = "slog"
+	} else {
+		writer, ok = lw.(io.Writer)
+		if ok {
+			target = "writer"
+		}
+	}
+
+	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		nw := &lResponseWriter{w, 0, 0, http.StatusOK, GetRequestID(r)}
+
+		if len(target) > 0 {
+			defer func(st time.Time) {
+				etime := time.Since(st)
+				rawSize := nw.uncompressedSize
+				if rawSize < 1 {
+					rawSize = nw.contentSize
+				}
+
+				switch target {
+				case "slog":
+					attrs := []any{
+						"method", r.Method,
+						"path", r.URL.Path,
+						"time_ms", etime,
+						"status", nw.statusCode,
+						"size", nw.contentSize,
+						"uncompressed_size", rawSize,
+					}
+					if len(nw.requestID) > 0 {
+						attrs = append(attrs, "request_id", nw.requestID)
+					}
+					sLogger.Info("http request", attrs...)
+				case "writer":
+					id := ""
+					if len(nw.requestID) > 0 {
+						id = ", ID " + nw.requestID
+					}
+					fmt.Fprintf(writer, "[%s] %s - (T %s, S %d, L %d, U %d%s)\n",
+						r.Method,
+						r.URL.Path,
+						etime,
+						nw.statusCode,
+						nw.contentSize,
+						rawSize,
+						id,
+					)
+				}
+			}(time.Now())
+		}
+
+		next.ServeHTTP(nw, r)
+	})
+}
+
+// PrepareMux middleware parses request to create cache data as needed
+// within optional [BodyConfig] limits
+func PrepareMux(configs ...*BodyConfig) NextHandler {
+	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		nr, err := PrepareBody(r, configs...)
+		if err != nil {
+			if _, ok := err.(*ResponseError); !ok {
+				err = Error(err.Error()).Server().Status(http.StatusUnprocessableEntity)
+			}
+			_ = WriteError(w, err, r)
+		} else {
+			if nr.MultipartForm != nil {
+				defer nr.MultipartForm.RemoveAll()
+			}
+			next.ServeHTTP(w, nr)
+		}
+	})
+}
+
+// RecoverHook defines callback to report recovered panics
+type RecoverHook func(*http.Request, *ResponseError)
+
+// Recover middleware builds handler to recover panics as server [ResponseError]
+// with caller location and stack trace; [http.ErrAbortHandler] is re-panicked
+func Recover(hooks ...RecoverHook) NextHandler {
+	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			} else if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err := Errorf("panic: %v", rec).
+				Server().
+				Reason("panic").
+				Stack(debug.Stack()).
+				RequestID(GetRequestID(r))
+
+			file, line, ok := panicCaller()
+			if ok {
+				err.Path(fmt.Sprintf("%s:%d", file, line))
+			}
+
+			for _, hook := range hooks {
+				hook(r, err)
+			}
+
+			_ = WriteError(w, err, r)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// panicCaller finds the first caller outside runtime after a panic
+func panicCaller() (string, int, bool) {
+	inRuntime := false
+	for skip := 2; skip < 64; skip++ {
+		file, line, ok := GetCaller(skip)
+		if !ok {
+			break
+		}
+
+		if strings.Contains(file, "/runtime/") {
+			inRuntime = true
+		} else if inRuntime {
+			return file, line, true
+		}
+	}
+	return "", 0, false
+}
+
+// RequestIDConfig defines options for [RequestID] middleware
+type RequestIDConfig struct {
+
+	// header to read and echo request id; default X-Request-Id
+	Header string
+
+	// generator for new request ids; default [UUIDv4]
+	Generator func() string
+}
+
+// NewRequestIDConfig creates default [RequestIDConfig]
+func NewRequestIDConfig() *RequestIDConfig {
+	return &RequestIDConfig{
+		Header:    "X-Request-Id",
+		Generator: UUIDv4,
+	}
+}
+
+// RequestID middleware builds handler to read or generate request id
+// and save it in request context and response header
+func RequestID(configs ...*RequestIDConfig) NextHandler {
+	config := NewRequestIDConfig()
+	if len(configs) > 0 {
+		if len(configs[0].Header) > 0 {
+			config.Header = configs[0].Header
+		}
+		if configs[0].Generator != nil {
+			config.Generator = configs[0].Generator
+		}
+	}
+
+	return M(func(w http.ResponseWriter, r *http.Request, next http.Handler) {
+		id := r.Header.Get(config.Header)
+		if !validRequestID(id) {
+			id = config.Generator()
+		}
+
+		if rw, ok := findWriter[requestIDWriter](w); ok {
+			rw.setRequestID(id)
+		}
+
+		w.Header().Set(config.Header, id)
+		next.ServeHTTP(w, Patch(r, ContextValueRequestID, id))
+	})
+}
+
+// GetRequestID retrieves request id saved by [RequestID] middleware
+func GetRequestID(r *http.Request) string {
+	id, _ := r.Context().Value(ContextValueRequestID).(string)
+	return id
+}
+
+// validRequestID checks if incoming request id is safe to reuse
+func validRequestID(id string) bool {
+	if len(id) < 1 || len(id) > 128 {
+		return false
+	}
+
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,270 @@
+package hndlor
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateState defines stored state of rate limit for a key
+type RateState struct {
+
+	// available tokens for token bucket
+	Tokens float64
+
+	// last update time
+	Last time.Time
+
+	// requests in current window for sliding window
+	Count int
+
+	// requests in previous window for sliding window
+	PrevCount int
+
+	// start time of current window
+	WindowStart time.Time
+}
+
+// RateResult defines result of rate limit check
+type RateResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateAlgorithm defines function to apply rate limit on stored state
+type RateAlgorithm func(state *RateState, limit int, window time.Duration, now time.Time) RateResult
+
+// Store defines storage for rate limit states; external backends
+// must apply update atomically per key
+type Store interface {
+	Update(key string, ttl time.Duration, fn func(*RateState)) error
+}
+
+// RateLimitConfig defines options for [RateLimit] middleware
+type RateLimitConfig struct {
+
+	// allowed requests per window
+	Limit int
+
+	// duration of window
+	Window time.Duration
+
+	// algorithm to apply; default [TokenBucket]
+	Algorithm RateAlgorithm
+
+	// storage of states; default [NewMemoryStore]
+	Store Store
+
+	// resolver of rate limit key; default [ClientIP]
+	Key ValueResolver
+
+	// namespace of keys in store; defaults to unique prefix per middleware,
+	// set explicitly when store is shared across processes
+	Prefix string
+}
+
+// rateLimitInstances counts middlewares for default key prefix
+var rateLimitInstances atomic.Uint64
+
+// TokenBucket refills limit tokens evenly over window allowing bursts up to limit
+func TokenBucket(state *RateState, limit int, window time.Duration, now time.Time) RateResult {
+	rate := float64(limit) / window.Seconds()
+	if state.Last.IsZero() {
+		state.Tokens = float64(limit)
+	} else {
+		state.Tokens = math.Min(float64(limit), state.Tokens+now.Sub(state.Last).Seconds()*rate)
+	}
+	state.Last = now
+
+	res := RateResult{Limit: limit}
+	if state.Tokens >= 1 {
+		state.Tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsDuration((1 - state.Tokens) / rate)
+	}
+
+	res.Remaining = int(state.Tokens)
+	res.Reset = secondsDuration((float64(limit) - state.Tokens) / rate)
+	return res
+}
+
+// SlidingWindow counts requests over window weighted with previous window
+func SlidingWindow(state *RateState, limit int, window time.Duration, now time.Time) RateResult {
+	start := now.Truncate(window)
+	if !state.WindowStart.Equal(start) {
+		if state.WindowStart.Add(window).Equal(start) {
+			state.PrevCount = state.Count
+		} else {
+			state.PrevCount = 0
+		}
+		state.Count = 0
+		state.WindowStart = start
+	}
+	state.Last = now
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(window)
+	estimated := float64(state.PrevCount)*weight + float64(state.Count)
+
+	res := RateResult{
+		Limit: limit,
+		Reset: window - elapsed,
+	}
+
+	if estimated+1 <= float64(limit) {
+		state.Count++
+		res.Allowed = true
+		res.Remaining = max(0, limit-int(math.Ceil(estimated+1)))
+	} else if state.Count >= limit || state.PrevCount < 1 {
+		res.RetryAfter = window - elapsed
+	} else {
+		// wait until weighted previous window frees a request
+		target := 1 - float64(limit-1-state.Count)/float64(state.PrevCount)
+		res.RetryAfter = time.Duration(target*float64(window)) - elapsed
+	}
+
+	return res
+}
+
+// secondsDuration converts seconds to [time.Duration]
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// memoryShards defines number of shards in [MemoryStore]
+const memoryShards = 32
+
+// memoryEntry defines state with expiry in [MemoryStore]
+type memoryEntry struct {
+	state   RateState
+	expires time.Time
+}
+
+// memoryShard defines locked partition of [MemoryStore]
+type memoryShard struct {
+	sync.Mutex
+	items map[string]*memoryEntry
+	ops   int
+}
+
+// MemoryStore defines in-memory sharded [Store]; zero value is ready to use
+type MemoryStore struct {
+	shards [memoryShards]memoryShard
+}
+
+// Update applies fn to state of key under shard lock
+func (s *MemoryStore) Update(key string, ttl time.Duration, fn func(*RateState)) error {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	shard := &s.shards[h.Sum32()%memoryShards]
+
+	now := time.Now()
+	shard.Lock()
+	defer shard.Unlock()
+
+	if shard.items == nil {
+		shard.items = make(map[string]*memoryEntry)
+	}
+
+	shard.ops++
+	if shard.ops%1024 == 0 {
+		for k, e := range shard.items {
+			if now.After(e.expires) {
+				delete(shard.items, k)
+			}
+		}
+	}
+
+	entry, ok := shard.items[key]
+	if !ok || now.After(entry.expires) {
+		entry = &memoryEntry{}
+		shard.items[key] = entry
+	}
+
+	fn(&entry.state)
+	entry.expires = now.Add(ttl)
+	return nil
+}
+
+// NewMemoryStore creates in-memory sharded [Store]
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// RateLimit middleware builds handler to limit requests per key
+// responding 429 with Retry-After and RateLimit-* headers;
+// panics on invalid Limit or Window
+func RateLimit(config *RateLimitConfig) NextHandler {
+	if config.Limit < 1 || config.Window <= 0 {
+		panic(fmt.Sprintf("invalid rate limit: %d per %s", config.Limit, config.Window))
+	}
+
+	prefix := config.Prefix
+	if len(prefix) < 1 {
+		prefix = fmt.Sprintf("ratelimit-%d", rateLimitInstances.Add(1))
+	}
+
+	algorithm := config.Algorithm
+	if algorithm == nil {
+		algorithm = TokenBucket
+	}
+	store := config.Store
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	key := config.Key
+	if key == nil {
+		key = ClientIP()
+	}
+
+	return MM(func(w http.ResponseWriter, r *http.Request, next http.Handler) error {
+		kv, err := key.Resolve(w, r)
+		if err != nil {
+			var rErr *ResponseError
+			if errors.As(err, &rErr) && rErr.ResponseStatus() >= http.StatusBadRequest {
+				return rErr
+			}
+			return Error("unable to resolve rate limit key").
+				Status(http.StatusBadRequest).
+				Reason("rate_limit_key_invalid")
+		}
+
+		var res RateResult
+		err = store.Update(prefix+":"+stringify(kv), 2*config.Window, func(state *RateState) {
+			res = algorithm(state, config.Limit, config.Window, time.Now())
+		})
+		if err != nil {
+			return Error(err.Error()).Server().Reason("rate_store_failed")
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+		if !res.Allowed {
+			header.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			return Error("rate limit exceeded").
+				Status(http.StatusTooManyRequests).
+				Reason("rate_limited")
+		}
+
+		next.ServeHTTP(w, r)
+		return nil
+	})
+}
+
+// ceilSeconds rounds duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
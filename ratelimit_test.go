@@ -0,0 +1,164 @@
+package hndlor_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	r := hndlor.Router().Use(hndlor.RateLimit(&hndlor.RateLimitConfig{
+		Limit:  2,
+		Window: time.Minute,
+	}))
+	r.HandleFunc("GET /ping", func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, "pong")
+	})
+
+	kr := hndlor.Router()
+	kr.Handle("GET /keyed", hndlor.Chain(hndlor.RateLimit(&hndlor.RateLimitConfig{
+		Limit:     1,
+		Window:    time.Minute,
+		Algorithm: hndlor.SlidingWindow,
+		Key:       hndlor.Header[string]("X-Api-Key"),
+	}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, "ok")
+	})))
+
+	for i, status := range []int{200, 200, 429} {
+		res, err := RunTestRequest(r, "GET", "/ping")
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := res.Result()
+
+		err = InvalidateTestResultStatus(response, status)
+		if err != nil {
+			t.Errorf("request %d: %s", i, err)
+		}
+		if response.Header.Get("RateLimit-Limit") != "2" {
+			t.Errorf("missing rate limit headers: %v", response.Header)
+		}
+
+		if status == 429 {
+			var data hndlor.JSON
+			err = RunTestResultDecode(response, &data)
+			if err != nil {
+				t.Error(err)
+			} else if data["reason"] != "rate_limited" || response.Header.Get("Retry-After") != "30" {
+				t.Errorf("invalid rate limited response: %v, %v", data, response.Header)
+			}
+		}
+	}
+
+	for i, key := range []string{"a", "b", "a"} {
+		status := 200
+		if i == 2 {
+			status = 429
+		}
+
+		res, err := RunTestRequest(kr, "GET", "/keyed", func(r *http.Request) {
+			r.Header.Set("X-Api-Key", key)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = InvalidateTestResultStatus(res.Result(), status)
+		if err != nil {
+			t.Errorf("request %d with key %s: %s", i, key, err)
+		}
+	}
+}
+
+func TestRateAlgorithms(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var state hndlor.RateState
+	for i := 0; i < 3; i++ {
+		if !hndlor.TokenBucket(&state, 3, time.Minute, start).Allowed {
+			t.Errorf("token bucket should allow burst request %d", i)
+		}
+	}
+	if hndlor.TokenBucket(&state, 3, time.Minute, start).Allowed {
+		t.Error("token bucket should deny empty bucket")
+	}
+	if !hndlor.TokenBucket(&state, 3, time.Minute, start.Add(20*time.Second)).Allowed {
+		t.Error("token bucket should refill over time")
+	}
+
+	state = hndlor.RateState{}
+	for i := 0; i < 4; i++ {
+		if !hndlor.SlidingWindow(&state, 4, time.Minute, start.Add(30*time.Second)).Allowed {
+			t.Errorf("sliding window should allow request %d", i)
+		}
+	}
+	res := hndlor.SlidingWindow(&state, 4, time.Minute, start.Add(30*time.Second))
+	if res.Allowed || res.RetryAfter != 30*time.Second {
+		t.Errorf("sliding window should deny full window: %+v", res)
+	}
+
+	// half of previous window still counts
+	res = hndlor.SlidingWindow(&state, 4, time.Minute, start.Add(90*time.Second))
+	if !res.Allowed || res.Remaining != 1 {
+		t.Errorf("sliding window should weight previous window: %+v", res)
+	}
+	if !hndlor.SlidingWindow(&state, 4, time.Minute, start.Add(90*time.Second)).Allowed {
+		t.Error("sliding window should allow last request")
+	}
+	res = hndlor.SlidingWindow(&state, 4, time.Minute, start.Add(90*time.Second))
+	if res.Allowed || res.RetryAfter != 15*time.Second {
+		t.Errorf("sliding window should deny with weighted retry: %+v", res)
+	}
+}
+
+func TestRateLimitConfig(t *testing.T) {
+	store := &hndlor.MemoryStore{}
+	route := hndlor.RateLimit(&hndlor.RateLimitConfig{
+		Limit:  1,
+		Window: time.Minute,
+		Store:  store,
+		Key:    hndlor.Header[string]("X-Api-Key"),
+	})
+
+	r := hndlor.Router().Use(hndlor.RateLimit(&hndlor.RateLimitConfig{
+		Limit:  1,
+		Window: time.Minute,
+		Store:  store,
+		Key:    hndlor.Header[string]("X-Api-Key"),
+	}))
+	r.Handle("GET /ping", route(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = hndlor.WriteMessage(w, "pong")
+	})))
+
+	for i, c := range []struct {
+		key    string
+		status int
+	}{{"", 400}, {"a", 200}, {"a", 429}} {
+		res, err := RunTestRequest(r, "GET", "/ping", func(r *http.Request) {
+			if len(c.key) > 0 {
+				r.Header.Set("X-Api-Key", c.key)
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = InvalidateTestResultStatus(res.Result(), c.status)
+		if err != nil {
+			t.Errorf("request %d: %s", i, err)
+		}
+	}
+
+	for _, config := range []*hndlor.RateLimitConfig{{Limit: 1}, {Window: time.Second}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected invalid config to panic: %+v", config)
+				}
+			}()
+			hndlor.RateLimit(config)
+		}()
+	}
+}
@@ -1,60 +1,216 @@
-package hndlor
-
-import (
-	"fmt"
-	"reflect"
-	"strconv"
-	"strings"
-)
-
-// ReadValue reads value as provided type or returns [error]
-func ReadValue[T any](tp reflect.Type, value any, fb T) (T, error) {
-	if tp == reflect.TypeOf(value) {
-		return value.(T), nil
-	}
-
-	str := fmt.Sprint(value)
-	switch tp.Kind() {
-	case reflect.Int, reflect.Int32, reflect.Int64:
-		val, err := strconv.ParseInt(str, 10, 64)
-		if err != nil {
-			return fb, err
-		}
-		return any(val).(T), nil
-	case reflect.Float32, reflect.Float64:
-		val, err := strconv.ParseFloat(str, 64)
-		if err != nil {
-			return fb, err
-		}
-		return any(val).(T), nil
-	case reflect.Bool:
-		val, err := strconv.ParseBool(str)
-		if err != nil {
-			return fb, err
-		}
-		return any(val).(T), nil
-	case reflect.String:
-		return value.(T), nil
-	}
-
-	return fb, nil
-}
-
-// ReadFields retrieves all exported fields for the struct
-func ReadFields(tp reflect.Type) []string {
-	el := tp
-	if el.Kind() == reflect.Ptr {
-		el = el.Elem()
-	}
-
-	keys := make([]string, 0)
-	fcount := el.NumField()
-	for i := range fcount {
-		f := el.Field(i)
-		if f.IsExported() {
-			keys = append(keys, strings.ToLower(f.Name))
-		}
-	}
-
-	return keys
-}
+package hndlor
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ReadValue reads value as provided type or returns [error]
+//
+// Supports every builtin kind, pointers, slices, [time.Time],
+// [time.Duration], [encoding.TextUnmarshaler] and types with
+// converter registered via [RegisterConverter]
+func ReadValue[T any](tp reflect.Type, value any, fb T) (T, error) {
+	rv, err := convertValue(tp, value)
+	if err != nil {
+		return fb, err
+	}
+
+	if rv.Kind() == reflect.Interface && rv.IsNil() {
+		var zero T
+		return zero, nil
+	}
+
+	val, ok := rv.Interface().(T)
+	if !ok {
+		return fb, convertError(value, tp, nil)
+	}
+	return val, nil
+}
+
+// isMultiValue checks if type is resolved from repeated values
+func isMultiValue(tp reflect.Type) bool {
+	return tp.Kind() == reflect.Slice && tp.Elem().Kind() != reflect.Uint8
+}
+
+// LookupValues reads repeated values of key including
+// bracket forms like key[]=1 and key[0]=1 from [url.Values]
+func LookupValues(values url.Values, key string) []any {
+	items := make([]any, 0)
+	for _, v := range values[key] {
+		items = append(items, v)
+	}
+	for _, v := range values[key+"[]"] {
+		items = append(items, v)
+	}
+
+	indexes := make([]int, 0)
+	for k := range values {
+		raw, ok := strings.CutPrefix(k, key+"[")
+		if ok && strings.HasSuffix(raw, "]") {
+			idx, err := strconv.Atoi(strings.TrimSuffix(raw, "]"))
+			if err == nil {
+				indexes = append(indexes, idx)
+			}
+		}
+	}
+
+	slices.Sort(indexes)
+	for _, idx := range indexes {
+		for _, v := range values[fmt.Sprintf("%s[%d]", key, idx)] {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
+
+// lookupSource reads the raw value of key from [ValueSource] as
+// list of values when multi is set and reports if value was found
+func lookupSource(r *http.Request, src ValueSource, key string, multi bool, codec CookieCodec) (any, bool, error) {
+	items := make([]any, 0)
+
+	switch src {
+	case ValueSourceGet:
+		if r.URL == nil {
+			break
+		}
+
+		query := r.URL.Query()
+		if multi {
+			items = LookupValues(query, key)
+		} else if query.Has(key) {
+			return query.Get(key), true, nil
+		}
+	case ValueSourceBody:
+		if multi {
+			if r.Form != nil {
+				items = LookupValues(r.Form, key)
+			}
+			if len(items) < 1 && r.PostForm != nil {
+				items = LookupValues(r.PostForm, key)
+			}
+			if len(items) > 0 {
+				break
+			}
+		}
+
+		bValue, ok := BodyRead(r, key)
+		if !ok {
+			break
+		} else if !multi {
+			return bValue, true, nil
+		} else if list, isList := bValue.([]any); isList {
+			items = list
+		} else {
+			items = []any{bValue}
+		}
+	case ValueSourcePath:
+		pVal := r.PathValue(key)
+		if len(pVal) < 1 {
+			break
+		} else if !multi {
+			return pVal, true, nil
+		}
+		items = []any{pVal}
+	case ValueSourceHeader:
+		for _, hVal := range r.Header.Values(key) {
+			if !multi {
+				return hVal, true, nil
+			}
+			items = append(items, hVal)
+		}
+	case ValueSourceCookie:
+		for _, c := range r.CookiesNamed(key) {
+			cVal := c.Value
+			if codec != nil {
+				var err error
+				cVal, err = codec.Decode(c.Name, c.Value)
+				if err != nil {
+					return nil, false, err
+				}
+			}
+
+			if !multi {
+				return cVal, true, nil
+			}
+			items = append(items, cVal)
+		}
+	case ValueSourceContext:
+		kv, ok := contextData(r)[key]
+		if !ok {
+			break
+		} else if !multi {
+			return kv, true, nil
+		}
+		items = expandSlice(kv)
+	case ValueSourceFile:
+		for _, fh := range FormFiles(r, key) {
+			if !multi {
+				return fh, true, nil
+			}
+			items = append(items, fh)
+		}
+	}
+
+	return items, len(items) > 0, nil
+}
+
+// readSlice converts every item to the element type of slice
+// after splitting string items by optional separator
+func readSlice[T any](tp reflect.Type, items []any, sep string, fb T) (T, error) {
+	if len(sep) > 0 {
+		parts := make([]any, 0, len(items))
+		for _, item := range items {
+			str, ok := item.(string)
+			if !ok {
+				parts = append(parts, item)
+				continue
+			}
+
+			for _, part := range strings.Split(str, sep) {
+				if part = strings.TrimSpace(part); len(part) > 0 {
+					parts = append(parts, part)
+				}
+			}
+		}
+		items = parts
+	}
+
+	rv, err := convertSlice(tp, items)
+	if err != nil {
+		return fb, err
+	}
+	return rv.Interface().(T), nil
+}
+
+// ReadFields retrieves names of all bindable exported fields for the struct
+func ReadFields(tp reflect.Type) []string {
+	plan := bindingPlanFor(tp)
+
+	keys := make([]string, len(plan.fields))
+	for i, fb := range plan.fields {
+		keys[i] = fb.name
+	}
+
+	return keys
+}
+
+// expandSlice expands slice value into items else wraps the value
+func expandSlice(value any) []any {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || !isMultiValue(rv.Type()) {
+		return []any{value}
+	}
+
+	items := make([]any, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items
+}
@@ -0,0 +1,115 @@
+package hndlor
+
+import (
+	"net/http"
+)
+
+// AsWritableResponse defines interface to write custom response
+type AsWritableResponse interface {
+
+	// WriteResponse writes status, headers and body to [http.ResponseWriter]
+	// negotiated for the *[http.Request]
+	WriteResponse(http.ResponseWriter, *http.Request) error
+}
+
+// Response defines struct with response status, headers, cookies and body
+type Response struct {
+
+	// http status code
+	statusCode int
+
+	// headers to apply on response
+	header http.Header
+
+	// cookies to set on response
+	cookies []*http.Cookie
+
+	// body data to serialize
+	body any
+}
+
+// Status updates the status code for response
+func (res *Response) Status(c int) *Response {
+	res.statusCode = c
+	return res
+}
+
+// Header adds header value for response
+func (res *Response) Header(key string, value string) *Response {
+	if res.header == nil {
+		res.header = make(http.Header)
+	}
+	res.header.Add(key, value)
+	return res
+}
+
+// Cookie adds cookie to set on response
+func (res *Response) Cookie(c *http.Cookie) *Response {
+	res.cookies = append(res.cookies, c)
+	return res
+}
+
+// Body updates the body data for response
+func (res *Response) Body(data any) *Response {
+	res.body = data
+	return res
+}
+
+// WriteResponse writes the response to [http.ResponseWriter] and
+// responds with 204 No Content when response is nil
+func (res *Response) WriteResponse(w http.ResponseWriter, r *http.Request) error {
+	if res == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+
+	h := w.Header()
+	for key, values := range res.header {
+		for _, v := range values {
+			h.Add(key, v)
+		}
+	}
+
+	for _, c := range res.cookies {
+		http.SetCookie(w, c)
+	}
+
+	if res.body == nil {
+		if res.statusCode > 0 {
+			w.WriteHeader(res.statusCode)
+		} else {
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return nil
+	}
+
+	return writeData(w, res.statusCode, res.body, r)
+}
+
+// Respond creates [Response] with body data
+func Respond(data any) *Response {
+	return &Response{
+		header:  make(http.Header),
+		cookies: make([]*http.Cookie, 0),
+		body:    data,
+	}
+}
+
+// Created creates [Response] with 201 status and location header
+func Created(location string, data any) *Response {
+	res := Respond(data).Status(http.StatusCreated)
+	if len(location) > 0 {
+		res.Header("Location", location)
+	}
+	return res
+}
+
+// Redirect creates [Response] to redirect to the location
+func Redirect(location string, code int) *Response {
+	return Respond(nil).Status(code).Header("Location", location)
+}
+
+// NoContent creates [Response] with 204 status
+func NoContent() *Response {
+	return Respond(nil).Status(http.StatusNoContent)
+}
@@ -1,119 +1,306 @@
-package hndlor_test
-
-import (
-	"fmt"
-	"net/http"
-	"reflect"
-	"testing"
-
-	"github.com/OpenRunic/hndlor"
-)
-
-type TestLoginCredentials struct {
-	Username string
-	Password string
-}
-
-func CreateMethodTestRouter() *hndlor.MuxRouter {
-	r := CreateTestRouter()
-
-	r.Handle("GET /hello/{name}", hndlor.New(func(name string) (hndlor.JSON, error) {
-		return hndlor.JSON{
-			"message": fmt.Sprintf("Hello %s!", name),
-		}, nil
-	}, hndlor.Path[string]("name")))
-
-	r.Handle("GET /ping/{name}", hndlor.New(func(w http.ResponseWriter, name string) {
-		_ = hndlor.WriteData(w, hndlor.JSON{
-			"message": fmt.Sprintf("Pong to %s!", name),
-		})
-	}, hndlor.HTTPResponseWriter(), hndlor.Path[string]("name")))
-
-	authGroup := CreateTestRouter("/auth")
-	authGroup.Handle("POST /login", hndlor.New(func(creds TestLoginCredentials) (hndlor.JSON, error) {
-		return hndlor.JSON{
-			"username": creds.Username,
-			"password": creds.Password,
-		}, nil
-	}, hndlor.Struct[TestLoginCredentials]()))
-	authGroup.MountTo(r)
-
-	return r
-}
-
-func TestGetRoute(t *testing.T) {
-	r := CreateMethodTestRouter()
-
-	res, err := RunTestRequest(r, "GET", "/hello/John")
-	if err != nil {
-		t.Fatal(err)
-	}
-	response := res.Result()
-
-	err = InvalidateTestResultStatus(response, 200)
-	if err != nil {
-		t.Error(err)
-	} else {
-		if response.Header.Get("Content-Type") != "application/json" {
-			t.Error("unable to resolve json response header")
-		}
-
-		var data hndlor.JSON
-		err := RunTestResultDecode(response, &data)
-		if err != nil {
-			t.Error(err)
-		} else if data["message"] != "Hello John!" {
-			t.Error("unable to resolve valid response data on GET")
-		}
-	}
-}
-
-func TestWriterAccessOnRoute(t *testing.T) {
-	r := CreateMethodTestRouter()
-
-	res, err := RunTestRequest(r, "GET", "/ping/John")
-	if err != nil {
-		t.Fatal(err)
-	}
-	response := res.Result()
-
-	err = InvalidateTestResultStatus(response, 200)
-	if err != nil {
-		t.Error(err)
-	} else {
-		var data hndlor.JSON
-		err := RunTestResultDecode(response, &data)
-		if err != nil {
-			t.Error(err)
-		} else if data["message"] != "Pong to John!" {
-			t.Error("unable to resolve valid response data on custom writer")
-		}
-	}
-}
-
-func TestPostRoute(t *testing.T) {
-	r := CreateMethodTestRouter()
-	sampleLoginData := TestLoginCredentials{
-		Username: "admin",
-		Password: "pass",
-	}
-
-	res, err := RunTestJSONRequest(r, "POST", "/auth/login", sampleLoginData)
-	if err != nil {
-		t.Fatal(err)
-	}
-	response := res.Result()
-
-	err = InvalidateTestResultStatus(response, 200)
-	if err != nil {
-		t.Error(err)
-	} else {
-		var data TestLoginCredentials
-		err := RunTestResultDecode(response, &data)
-		if err != nil {
-			t.Error(err)
-		} else if !reflect.DeepEqual(sampleLoginData, data) {
-			t.Error("unable to resolve valid response data on POST")
-		}
-	}
-}
+package hndlor_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+type TestLoginCredentials struct {
+	Username string
+	Password string
+}
+
+func CreateMethodTestRouter() *hndlor.MuxRouter {
+	r := CreateTestRouter()
+
+	r.Handle("GET /hello/{name}", hndlor.New(func(name string) (hndlor.JSON, error) {
+		return hndlor.JSON{
+			"message": fmt.Sprintf("Hello %s!", name),
+		}, nil
+	}, hndlor.Path[string]("name")))
+
+	r.Handle("GET /ping/{name}", hndlor.New(func(w http.ResponseWriter, name string) {
+		_ = hndlor.WriteData(w, hndlor.JSON{
+			"message": fmt.Sprintf("Pong to %s!", name),
+		})
+	}, hndlor.HTTPResponseWriter(), hndlor.Path[string]("name")))
+
+	r.Handle("GET /greet/{name}", hndlor.New2(func(name string, times int) (hndlor.JSON, error) {
+		return hndlor.JSON{
+			"message": fmt.Sprintf("Hello %s!", name),
+			"times":   times,
+		}, nil
+	}, hndlor.Path[string]("name"), hndlor.Get[int]("times")).OnFail(func(_ hndlor.ValueResolver, _ error) error {
+		return nil
+	}))
+
+	r.Handle("GET /user/{name}", hndlor.New(func(name string) (*TestLoginCredentials, error) {
+		return &TestLoginCredentials{Username: name}, nil
+	}, hndlor.Path[string]("name")))
+
+	r.Handle("DELETE /user/{name}", hndlor.New(func(_ string) error {
+		return nil
+	}, hndlor.Path[string]("name")))
+
+	r.Handle("POST /user/{name}", hndlor.New(func(name string) (*hndlor.Response, error) {
+		return hndlor.Created("/user/"+name, TestLoginCredentials{Username: name}).
+			Cookie(&http.Cookie{Name: "uid", Value: name}), nil
+	}, hndlor.Path[string]("name")))
+
+	authGroup := CreateTestRouter("/auth")
+	authGroup.Handle("POST /login", hndlor.New(func(creds TestLoginCredentials) (hndlor.JSON, error) {
+		return hndlor.JSON{
+			"username": creds.Username,
+			"password": creds.Password,
+		}, nil
+	}, hndlor.Struct[TestLoginCredentials]()))
+	authGroup.MountTo(r)
+
+	return r
+}
+
+func TestGetRoute(t *testing.T) {
+	r := CreateMethodTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/hello/John")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		if response.Header.Get("Content-Type") != "application/json" {
+			t.Error("unable to resolve json response header")
+		}
+
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["message"] != "Hello John!" {
+			t.Error("unable to resolve valid response data on GET")
+		}
+	}
+}
+
+func TestWriterAccessOnRoute(t *testing.T) {
+	r := CreateMethodTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/ping/John")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["message"] != "Pong to John!" {
+			t.Error("unable to resolve valid response data on custom writer")
+		}
+	}
+}
+
+func TestPostRoute(t *testing.T) {
+	r := CreateMethodTestRouter()
+	sampleLoginData := TestLoginCredentials{
+		Username: "admin",
+		Password: "pass",
+	}
+
+	res, err := RunTestJSONRequest(r, "POST", "/auth/login", sampleLoginData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data TestLoginCredentials
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if !reflect.DeepEqual(sampleLoginData, data) {
+			t.Error("unable to resolve valid response data on POST")
+		}
+	}
+}
+
+func TestTypedRoute(t *testing.T) {
+	r := CreateMethodTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/greet/John?times=oops")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data["message"] != "Hello John!" || data["times"] != float64(0) {
+			t.Error("unable to resolve valid response data on typed handler")
+		}
+	}
+}
+
+func TestTypedResponseRoute(t *testing.T) {
+	r := CreateMethodTestRouter()
+
+	res, err := RunTestRequest(r, "GET", "/user/John")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data TestLoginCredentials
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data.Username != "John" {
+			t.Error("unable to resolve typed response data")
+		}
+	}
+}
+
+func TestNoContentRoute(t *testing.T) {
+	r := CreateMethodTestRouter()
+
+	res, err := RunTestRequest(r, "DELETE", "/user/John")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InvalidateTestResultStatus(res.Result(), 204)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestResponseRoute(t *testing.T) {
+	r := CreateMethodTestRouter()
+
+	res, err := RunTestJSONRequest(r, "POST", "/user/John", hndlor.JSON{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 201)
+	if err != nil {
+		t.Error(err)
+	} else if response.Header.Get("Location") != "/user/John" {
+		t.Error("unable to resolve location header on response")
+	} else if len(response.Cookies()) != 1 || response.Cookies()[0].Value != "John" {
+		t.Error("unable to resolve cookie on response")
+	} else {
+		var data TestLoginCredentials
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Error(err)
+		} else if data.Username != "John" {
+			t.Error("unable to resolve response body")
+		}
+	}
+}
+
+type TestUpdateRequest struct {
+	ID      int    `hndlor:"path=id"`
+	Notify  bool   `hndlor:"query=notify"`
+	Token   string `hndlor:"header=X-Token"`
+	Session string `hndlor:"cookie=sid"`
+	Name    string `hndlor:"body=name"`
+	Tags    []string
+	Ignored string `hndlor:"-"`
+}
+
+func TestTaggedStructRoute(t *testing.T) {
+	r := CreateTestRouter()
+	r.Handle("PUT /items/{id}", hndlor.New(func(req TestUpdateRequest) (TestUpdateRequest, error) {
+		return req, nil
+	}, hndlor.Struct[TestUpdateRequest]()))
+
+	res, err := RunTestRequestBody(r, "PUT", "/items/42?notify=true", strings.NewReader(`{"name":"item","tags":["a","b"],"ignored":"x"}`), func(req *http.Request) {
+		req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+		req.Header.Set("X-Token", "t0k")
+		req.AddCookie(&http.Cookie{Name: "sid", Value: "s1d"})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data TestUpdateRequest
+		err := RunTestResultDecode(response, &data)
+		expected := TestUpdateRequest{
+			ID:      42,
+			Notify:  true,
+			Token:   "t0k",
+			Session: "s1d",
+			Name:    "item",
+			Tags:    []string{"a", "b"},
+		}
+		if err != nil {
+			t.Error(err)
+		} else if !reflect.DeepEqual(data, expected) {
+			t.Errorf("unable to bind tagged struct: %+v", data)
+		}
+	}
+}
+
+func TestZeroValueResponse(t *testing.T) {
+	res := &hndlor.Response{}
+	res.Header("X-Trace", "1").Status(http.StatusAccepted)
+
+	rec := httptest.NewRecorder()
+	err := res.WriteResponse(rec, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusAccepted || rec.Header().Get("X-Trace") != "1" {
+		t.Errorf("unable to write zero value response: %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestNilResponse(t *testing.T) {
+	h := hndlor.New(func() (*hndlor.Response, error) {
+		return nil, nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("expected 204 for nil response but received %d", rec.Code)
+	}
+}
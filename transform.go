@@ -0,0 +1,28 @@
+package hndlor
+
+import (
+	"cmp"
+	"strings"
+)
+
+// Trim removes leading and trailing white space from value
+func Trim[T ~string](v T) (T, error) {
+	return T(strings.TrimSpace(string(v))), nil
+}
+
+// Lowercase converts value to lower case
+func Lowercase[T ~string](v T) (T, error) {
+	return T(strings.ToLower(string(v))), nil
+}
+
+// Uppercase converts value to upper case
+func Uppercase[T ~string](v T) (T, error) {
+	return T(strings.ToUpper(string(v))), nil
+}
+
+// Clamp builds transformation to limit value within range
+func Clamp[T cmp.Ordered](lo T, hi T) func(T) (T, error) {
+	return func(v T) (T, error) {
+		return min(max(v, lo), hi), nil
+	}
+}
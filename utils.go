@@ -1,29 +1,77 @@
-package hndlor
-
-import (
-	"encoding/json"
-	"net"
-	"net/http"
-	"runtime"
-)
-
-// RequestAddr retrieves the requesting address info
-func RequestAddr(r *http.Request) net.Addr {
-	return r.Context().Value(http.LocalAddrContextKey).(net.Addr)
-}
-
-// GetCaller retrieves the runtime.Caller info
-func GetCaller(skip int) (string, int, bool) {
-	_, file, no, ok := runtime.Caller(skip)
-	return file, no, ok
-}
-
-// StructToStruct convert struct type
-func StructToStruct(src any, data any) error {
-	bt, err := json.Marshal(src)
-	if err != nil {
-		return err
-	}
-
-	return json.Unmarshal(bt, data)
-}
+package hndlor
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"runtime"
+	"time"
+)
+
+// crockford is the base32 alphabet used by ULID
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// RequestAddr retrieves the requesting address info
+func RequestAddr(r *http.Request) net.Addr {
+	return r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+}
+
+// GetCaller retrieves the runtime.Caller info
+func GetCaller(skip int) (string, int, bool) {
+	_, file, no, ok := runtime.Caller(skip)
+	return file, no, ok
+}
+
+// StructToStruct convert struct type
+func StructToStruct(src any, data any) error {
+	bt, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(bt, data)
+}
+
+// UUIDv4 generates random version 4 uuid
+func UUIDv4() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+
+	return string(buf[:])
+}
+
+// ULID generates lexicographically sortable id with millisecond timestamp
+func ULID() string {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixMilli())<<16)
+	_, _ = rand.Read(b[6:])
+
+	// 128 bits encoded as 26 characters of 5 bits each
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	var buf [26]byte
+	for i := 25; i >= 0; i-- {
+		buf[i] = crockford[lo&0x1f]
+		lo = (lo >> 5) | (hi << 59)
+		hi >>= 5
+	}
+
+	return string(buf[:])
+}
@@ -0,0 +1,283 @@
+package hndlor
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// tag name used to define validation rules of struct field
+const validationTag = "validate"
+
+// Rule defines validation check for value and
+// returns failure message or empty string when valid
+type Rule func(reflect.Value) string
+
+// ruleBuilders creates [Rule] from tag rule name and parameter
+var ruleBuilders = map[string]func(string) (Rule, error){
+	"required": func(string) (Rule, error) { return RuleRequired(), nil },
+	"email":    func(string) (Rule, error) { return RuleEmail(), nil },
+	"url":      func(string) (Rule, error) { return RuleURL(), nil },
+	"min": func(p string) (Rule, error) {
+		n, err := strconv.ParseFloat(p, 64)
+		return RuleMin(n), err
+	},
+	"max": func(p string) (Rule, error) {
+		n, err := strconv.ParseFloat(p, 64)
+		return RuleMax(n), err
+	},
+	"len": func(p string) (Rule, error) {
+		n, err := strconv.Atoi(p)
+		return RuleLen(n), err
+	},
+	"oneof": func(p string) (Rule, error) {
+		return RuleOneOf(strings.Fields(p)...), nil
+	},
+}
+
+// ValidationError creates [ResponseError] with 422 status
+// and failure messages of every field in extras as errors
+func ValidationError(errs JSON) *ResponseError {
+	return Error("validation failed").
+		Status(http.StatusUnprocessableEntity).
+		Reason("validation_failed").
+		Extras(JSON{"errors": errs})
+}
+
+// parseRules builds list of [Rule] from validate tag
+// i.e. `validate:"required,min=3,max=64,email,oneof=a b c"`
+// where omitempty skips the rules on empty value
+func parseRules(tag string) ([]Rule, error) {
+	rules := make([]Rule, 0)
+	omitEmpty := false
+
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if len(name) < 1 {
+			continue
+		} else if name == "omitempty" {
+			omitEmpty = true
+			continue
+		}
+
+		builder, ok := ruleBuilders[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown validation rule [%s]", name)
+		}
+
+		rule, err := builder(param)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validation rule [%s]: %s", part, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if omitEmpty {
+		for i, rule := range rules {
+			rules[i] = skipEmpty(rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// indirect dereferences the pointer value
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.IsValid() && rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// measure reads the comparable size of value with its unit
+func measure(rv reflect.Value) (float64, string, bool) {
+	switch rv.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(rv.String())), " characters", true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(rv.Len()), " items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), "", true
+	}
+	return 0, "", false
+}
+
+// skipEmpty wraps rule to skip check on empty values
+func skipEmpty(rule Rule) Rule {
+	return func(rv reflect.Value) string {
+		rv = indirect(rv)
+		if !rv.IsValid() || rv.IsZero() {
+			return ""
+		}
+		return rule(rv)
+	}
+}
+
+// present wraps rule to skip check on nil values
+func present(cb func(reflect.Value) string) Rule {
+	return func(rv reflect.Value) string {
+		rv = indirect(rv)
+		if !rv.IsValid() {
+			return ""
+		}
+		return cb(rv)
+	}
+}
+
+// RuleRequired validates value isn't empty
+func RuleRequired() Rule {
+	return func(rv reflect.Value) string {
+		rv = indirect(rv)
+		if !rv.IsValid() || rv.IsZero() {
+			return "is required"
+		}
+
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Map:
+			if rv.Len() < 1 {
+				return "is required"
+			}
+		}
+		return ""
+	}
+}
+
+// RuleMin validates minimum number or length of string and lists
+func RuleMin(n float64) Rule {
+	return present(func(rv reflect.Value) string {
+		size, unit, ok := measure(rv)
+		if ok && size < n {
+			return fmt.Sprintf("must be at least %v%s", n, unit)
+		}
+		return ""
+	})
+}
+
+// RuleMax validates maximum number or length of string and lists
+func RuleMax(n float64) Rule {
+	return present(func(rv reflect.Value) string {
+		size, unit, ok := measure(rv)
+		if ok && size > n {
+			return fmt.Sprintf("must be at most %v%s", n, unit)
+		}
+		return ""
+	})
+}
+
+// RuleLen validates exact length of string and lists
+func RuleLen(n int) Rule {
+	return present(func(rv reflect.Value) string {
+		size, unit, ok := measure(rv)
+		if ok && len(unit) > 0 && int(size) != n {
+			return fmt.Sprintf("must be exactly %d%s", n, unit)
+		}
+		return ""
+	})
+}
+
+// RuleEmail validates value is an email address
+func RuleEmail() Rule {
+	return present(func(rv reflect.Value) string {
+		str := fmt.Sprint(rv.Interface())
+		addr, err := mail.ParseAddress(str)
+		if err != nil || addr.Address != str {
+			return "must be a valid email address"
+		}
+		return ""
+	})
+}
+
+// RuleURL validates value is an absolute url
+func RuleURL() Rule {
+	return present(func(rv reflect.Value) string {
+		u, err := url.ParseRequestURI(fmt.Sprint(rv.Interface()))
+		if err != nil || len(u.Scheme) < 1 || len(u.Host) < 1 {
+			return "must be a valid url"
+		}
+		return ""
+	})
+}
+
+// RulePattern validates value matches the regular expression
+func RulePattern(re *regexp.Regexp) Rule {
+	return present(func(rv reflect.Value) string {
+		if !re.MatchString(fmt.Sprint(rv.Interface())) {
+			return fmt.Sprintf("must match pattern %s", re)
+		}
+		return ""
+	})
+}
+
+// RuleOneOf validates formatted value is one of the options
+func RuleOneOf(options ...string) Rule {
+	return present(func(rv reflect.Value) string {
+		if !slices.Contains(options, fmt.Sprint(rv.Interface())) {
+			return fmt.Sprintf("must be one of [%s]", strings.Join(options, " "))
+		}
+		return ""
+	})
+}
+
+// checkRules runs rules on value and returns first failure message
+func checkRules(rules []Rule, rv reflect.Value) string {
+	for _, rule := range rules {
+		if msg := rule(rv); len(msg) > 0 {
+			return msg
+		}
+	}
+	return ""
+}
+
+// validateStruct runs field rules of struct recursively and
+// collects failures in errs keyed by field names
+func validateStruct(rv reflect.Value, prefix string, errs JSON) {
+	rv = indirect(rv)
+	if !rv.IsValid() || rv.Kind() != reflect.Struct || isScalar(rv.Type()) {
+		return
+	}
+
+	plan := bindingPlanFor(rv.Type())
+	if !plan.validated {
+		return
+	}
+
+	for _, fb := range plan.fields {
+		fv := rv.FieldByIndex(fb.index)
+		key := fb.name
+		if len(prefix) > 0 {
+			key = prefix + "." + key
+		}
+
+		if msg := checkRules(fb.rules, fv); len(msg) > 0 {
+			errs[key] = msg
+		} else if fb.nested {
+			validateStruct(fv, key, errs)
+		}
+	}
+}
+
+// Validate runs validate tags of struct fields and returns
+// [ValidationError] with every failed field
+func Validate(data any) error {
+	errs := make(JSON)
+	validateStruct(reflect.ValueOf(data), "", errs)
+
+	if len(errs) > 0 {
+		return ValidationError(errs)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package hndlor_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+type TestAddress struct {
+	City string `validate:"required"`
+}
+
+type TestSignup struct {
+	Username string `validate:"required,min=3,max=16"`
+	Email    string `validate:"required,email"`
+	Role     string `validate:"omitempty,oneof=admin user"`
+	Age      int    `validate:"omitempty,min=18"`
+	Address  TestAddress
+}
+
+func TestStructValidation(t *testing.T) {
+	r := CreateTestRouter()
+	r.Handle("POST /signup", hndlor.New(func(data TestSignup) (TestSignup, error) {
+		return data, nil
+	}, hndlor.Struct[TestSignup]()))
+
+	res, err := RunTestJSONRequest(r, "POST", "/signup", hndlor.JSON{
+		"username": "ab",
+		"email":    "invalid",
+		"role":     "guest",
+		"age":      12,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 422)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errs, _ := data["errors"].(map[string]any)
+		if data["reason"] != "validation_failed" || len(errs) != 5 || errs["address.city"] == nil {
+			t.Errorf("unable to resolve validation errors: %v", data)
+		}
+	}
+
+	res, err = RunTestJSONRequest(r, "POST", "/signup", hndlor.JSON{
+		"username": "admin",
+		"email":    "admin@example.com",
+		"address":  hndlor.JSON{"city": "Kathmandu"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = InvalidateTestResultStatus(res.Result(), 200)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValueRules(t *testing.T) {
+	req, err := http.NewRequest("GET", "/?page=0&sort=name&code=AB-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = hndlor.Values(nil, req,
+		hndlor.Get[int]("page").Min(1).Max(100),
+		hndlor.Get[string]("sort").OneOf("name", "date"),
+		hndlor.Get[string]("code").Pattern(`^[A-Z]{2}-\d+$`),
+	)
+	if err == nil {
+		t.Fatal("unable to validate value rules")
+	}
+
+	re, ok := err.(*hndlor.ResponseError)
+	if !ok || re.ResponseStatus() != 422 {
+		t.Errorf("unable to resolve validation error: %v", err)
+	}
+}
+
+func TestAggregatedValues(t *testing.T) {
+	r := CreateTestRouter()
+	r.Handle("GET /search", hndlor.New3(func(q string, page int, limit int) (hndlor.JSON, error) {
+		return hndlor.JSON{"q": q, "page": page, "limit": limit}, nil
+	},
+		hndlor.Get[string]("q").Min(3),
+		hndlor.Get[int]("page"),
+		hndlor.Get[int]("limit"),
+	).Aggregate().OnFail(func(vr hndlor.ValueResolver, err error) error {
+		if vr.Alias() == "limit" {
+			return nil
+		}
+		return err
+	}))
+
+	res, err := RunTestRequest(r, "GET", "/search?q=ab&page=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 422)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		errs, _ := data["errors"].(map[string]any)
+		if data["reason"] != "validation_failed" || len(errs) != 2 || errs["q"] == nil || errs["page"] == nil {
+			t.Errorf("unable to resolve aggregated errors: %v", data)
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/?page=x&limit=y", nil)
+	_, err = hndlor.ValuesAll(nil, req, hndlor.Get[int]("page"), hndlor.Get[int]("limit"))
+	if re, ok := err.(*hndlor.ResponseError); !ok || re.ResponseStatus() != 422 {
+		t.Errorf("unable to aggregate values: %v", err)
+	}
+}
+
+func TestAggregatedServerErrors(t *testing.T) {
+	r := CreateTestRouter()
+	r.Handle("GET /tenant", hndlor.New2(func(page int, tenant string) (hndlor.JSON, error) {
+		return hndlor.JSON{"page": page, "tenant": tenant}, nil
+	},
+		hndlor.Get[int]("page"),
+		hndlor.Context[string]("tenant"),
+	).Aggregate())
+
+	res, err := RunTestRequest(r, "GET", "/tenant?page=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 500)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Fatal(err)
+		} else if data["reason"] == "validation_failed" || strings.Contains(fmt.Sprint(data), "tenant") {
+			t.Errorf("server error should not be aggregated: %v", data)
+		}
+	}
+}
+
+func TestAggregatedFailDefaults(t *testing.T) {
+	r := CreateTestRouter()
+	r.Handle("GET /page", hndlor.New(func(page int) (hndlor.JSON, error) {
+		return hndlor.JSON{"page": page}, nil
+	}, hndlor.Get[int]("page").Default(5).Min(10)).Aggregate().OnFail(func(_ hndlor.ValueResolver, _ error) error {
+		return nil
+	}))
+
+	res, err := RunTestRequest(r, "GET", "/page?page=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := res.Result()
+
+	err = InvalidateTestResultStatus(response, 200)
+	if err != nil {
+		t.Error(err)
+	} else {
+		var data hndlor.JSON
+		err := RunTestResultDecode(response, &data)
+		if err != nil {
+			t.Fatal(err)
+		} else if data["page"] != float64(5) {
+			t.Errorf("suppressed failure should use default value: %v", data)
+		}
+	}
+}
@@ -1,308 +1,543 @@
-package hndlor
-
-import (
-	"net/http"
-	"reflect"
-)
-
-// ValueSource defines the source of value
-type ValueSource int
-
-const (
-	ValueSourcePath    ValueSource = iota // reads from path params
-	ValueSourceGet                        // reads from url query
-	ValueSourceBody                       // reads from request body
-	ValueSourceHeader                     // reads from request header
-	ValueSourceContext                    // reads from request context default data
-	ValueSourceDefault                    // reads from source based on request method
-)
-
-// ValueResolver defines an interface to be used by handler
-type ValueResolver interface {
-
-	// Field returns the name of the field
-	Field() string
-
-	// Alias returns the alias of the field
-	Alias() string
-
-	// Type returns [reflect.Type] of value
-	Type() reflect.Type
-
-	// Default returns default value of type
-	Default() any
-
-	// Checks if value is required
-	Required() bool
-
-	// Resolve evaluates and return the resulting value else error
-	Resolve(http.ResponseWriter, *http.Request) (any, error)
-}
-
-// Value[T any] defines struct for resolving value from sources
-type Value[T any] struct {
-
-	// name of the field
-	field string
-
-	// alias name of the field
-	alias string
-
-	// if value is required
-	required bool
-
-	// source of value to read from
-	source ValueSource
-
-	// custom value reader
-	reader func(http.ResponseWriter, *http.Request) (T, error)
-
-	// validate value
-	validate func(*http.Request, T) error
-
-	// [reflect.Type] resolved from [T]
-	rType reflect.Type
-
-	// default value resolved for given [T]
-	rDefault T
-}
-
-func (v *Value[T]) Field() string {
-	return v.field
-}
-
-func (v *Value[T]) Alias() string {
-	if len(v.alias) > 0 {
-		return v.alias
-	}
-	return v.field
-}
-
-func (v *Value[T]) Type() reflect.Type {
-	return v.rType
-}
-
-func (v *Value[T]) Default() any {
-	return v.rDefault
-}
-
-func (v *Value[T]) Required() bool {
-	return v.required
-}
-
-// As sets alias name for field
-func (v *Value[T]) As(n string) *Value[T] {
-	v.alias = n
-	return v
-}
-
-// Optional marks value resolver as optional value
-func (v *Value[T]) Optional() *Value[T] {
-	v.required = false
-	return v
-}
-
-// Validate adds value validator to resolved value
-func (v *Value[T]) Validate(cb func(*http.Request, T) error) *Value[T] {
-	v.validate = cb
-	return v
-}
-
-// Reader stores custom value reader for value resolver
-func (v *Value[T]) Reader(cb func(http.ResponseWriter, *http.Request) (T, error)) *Value[T] {
-	v.reader = cb
-	return v
-}
-
-// readValue reads the value from *[http.Request] for provided [ValueSource]
-func (v *Value[T]) readValue(r *http.Request, src ValueSource) (T, error) {
-	asStruct := (v.rType.Kind() == reflect.Struct ||
-		(v.rType.Kind() == reflect.Ptr && v.rType.Elem().Kind() == reflect.Struct))
-
-	if asStruct {
-		var data T
-
-		if src == ValueSourceBody {
-			err := BodyReadStruct(r, &data)
-			if err != nil {
-				return v.rDefault, err
-			}
-			return data, nil
-		}
-
-		fields := ReadFields(v.rType)
-		values := make(map[string]any)
-
-		switch src {
-		case ValueSourceGet:
-			for key := range r.URL.Query() {
-				values[key] = r.URL.Query().Get(key)
-			}
-		case ValueSourcePath:
-			for _, key := range fields {
-				if len(r.PathValue(key)) > 0 {
-					values[key] = r.PathValue(key)
-				}
-			}
-		case ValueSourceHeader:
-			for _, key := range fields {
-				if len(r.Header.Get(key)) > 0 {
-					values[key] = r.Header.Get(key)
-				}
-			}
-		case ValueSourceContext:
-			for _, key := range fields {
-				kv, err := GetData[any](r, key, nil)
-				if err != nil {
-					return v.rDefault, err
-				}
-				values[key] = kv
-			}
-		}
-
-		if len(values) > 0 {
-			err := StructToStruct(values, &data)
-			if err != nil {
-				return v.rDefault, err
-			}
-			return data, nil
-		}
-	} else {
-		switch src {
-		case ValueSourceGet:
-			if r.URL != nil && r.URL.Query().Has(v.field) {
-				return ReadValue(v.rType, r.URL.Query().Get(v.field), v.rDefault)
-			}
-		case ValueSourceBody:
-			bValue, ok := BodyRead(r, v.field)
-			if ok {
-				return ReadValue(v.rType, bValue, v.rDefault)
-			}
-		case ValueSourcePath:
-			pVal := r.PathValue(v.field)
-			if len(pVal) > 0 {
-				return ReadValue(v.rType, pVal, v.rDefault)
-			}
-		case ValueSourceHeader:
-			hVal, ok := r.Header[v.field]
-			if ok {
-				return ReadValue(v.rType, hVal[0], v.rDefault)
-			}
-		case ValueSourceContext:
-			return GetData(r, v.field, v.rDefault)
-		}
-	}
-
-	return v.rDefault, Errorf("resolve value failed [%s]", v.field).Reason("value_failed")
-}
-
-// readDefaultValue reads the value from *[http.Request] based on request method
-func (v Value[T]) readDefaultValue(r *http.Request) (T, error) {
-	var value T
-	var err error
-
-	sources := make([]ValueSource, 0)
-	if HasBody(r) {
-		sources = append(sources, ValueSourceBody)
-	} else {
-		sources = append(sources, ValueSourceGet, ValueSourcePath)
-	}
-
-	for _, t := range sources {
-		value, err = v.readValue(r, t)
-		if err == nil {
-			return value, nil
-		}
-	}
-
-	return value, err
-}
-
-func (v Value[T]) Resolve(w http.ResponseWriter, r *http.Request) (any, error) {
-	if v.reader != nil {
-		return v.reader(w, r)
-	}
-
-	var value T
-	var err error
-	if v.source == ValueSourceDefault {
-		value, err = v.readDefaultValue(r)
-	} else {
-		value, err = v.readValue(r, v.source)
-	}
-
-	if err != nil {
-		if v.required {
-			return v.rDefault, err
-		}
-
-		return v.rDefault, nil
-	}
-
-	if v.validate != nil {
-		err := v.validate(r, value)
-		if err != nil {
-			return v.rDefault, err
-		}
-	}
-
-	return value, nil
-}
-
-// NewValue define new value resolver instance
-func NewValue[T any](field string, src ValueSource) *Value[T] {
-	tp := reflect.TypeOf((*T)(nil)).Elem()
-
-	var def T
-	if tp.Kind() != reflect.Interface {
-		def = (reflect.New(tp).Elem().Interface()).(T)
-	}
-
-	return &Value[T]{
-		field:    field,
-		rType:    tp,
-		rDefault: def,
-		source:   src,
-		required: true,
-	}
-}
-
-// StructFrom defines new struct resolver instance
-func StructFrom[T any](src ValueSource) *Value[T] {
-	return NewValue[T]("", src)
-}
-
-// Struct defines struct resolver for default source
-func Struct[T any]() *Value[T] {
-	return StructFrom[T](ValueSourceDefault)
-}
-
-// Get defines value resolver from query string
-func Get[T any](field string) *Value[T] {
-	return NewValue[T](field, ValueSourceGet)
-}
-
-// Body defines value resolver from request body
-func Body[T any](field string) *Value[T] {
-	return NewValue[T](field, ValueSourceBody)
-}
-
-// Path defines value resolver from url path params
-func Path[T any](field string) *Value[T] {
-	return NewValue[T](field, ValueSourcePath)
-}
-
-// Header defines value resolver from request header
-func Header[T any](field string) *Value[T] {
-	return NewValue[T](field, ValueSourceHeader)
-}
-
-// Context defines value resolver from default data on request context
-func Context[T any](key string) *Value[T] {
-	return NewValue[T](key, ValueSourceContext)
-}
-
-// Reader defines value resolver using custom reader
-func Reader[T any](cb func(http.ResponseWriter, *http.Request) (T, error)) *Value[T] {
-	return NewValue[T]("", ValueSourceDefault).Reader(cb)
-}
+package hndlor
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ValueSource defines the source of value
+type ValueSource int
+
+const (
+	ValueSourcePath    ValueSource = iota // reads from path params
+	ValueSourceGet                        // reads from url query
+	ValueSourceBody                       // reads from request body
+	ValueSourceHeader                     // reads from request header
+	ValueSourceContext                    // reads from request context default data
+	ValueSourceDefault                    // reads from source based on request method
+	ValueSourceFile                       // reads from uploaded multipart files
+	ValueSourceCookie                     // reads from request cookies
+)
+
+// ValueResolver defines an interface to be used by handler
+type ValueResolver interface {
+
+	// Field returns the name of the field
+	Field() string
+
+	// Alias returns the alias of the field
+	Alias() string
+
+	// Type returns [reflect.Type] of value
+	Type() reflect.Type
+
+	// DefaultValue returns fallback value for the request
+	DefaultValue(*http.Request) any
+
+	// Checks if value is required
+	Required() bool
+
+	// Resolve evaluates and return the resulting value else error
+	Resolve(http.ResponseWriter, *http.Request) (any, error)
+}
+
+// Value[T any] defines struct for resolving value from sources
+type Value[T any] struct {
+
+	// name of the field
+	field string
+
+	// alias name of the field
+	alias string
+
+	// if value is required
+	required bool
+
+	// source of value to read from
+	source ValueSource
+
+	// custom value reader
+	reader func(http.ResponseWriter, *http.Request) (T, error)
+
+	// validate value
+	validate func(*http.Request, T) error
+
+	// codec to decode cookie values
+	codec CookieCodec
+
+	// reject unknown fields when decoding body
+	strict bool
+
+	// separator to split values for slice type
+	separator string
+
+	// validation rules for resolved value
+	rules []Rule
+
+	// transformations applied before validation
+	transforms []func(T) (T, error)
+
+	// default value resolver for optional value
+	defaultFunc func(*http.Request) T
+
+	// [reflect.Type] resolved from [T]
+	rType reflect.Type
+
+	// default value resolved for given [T]
+	rDefault T
+}
+
+func (v *Value[T]) Field() string {
+	return v.field
+}
+
+func (v *Value[T]) Alias() string {
+	if len(v.alias) > 0 {
+		return v.alias
+	}
+	return v.field
+}
+
+func (v *Value[T]) Type() reflect.Type {
+	return v.rType
+}
+
+func (v *Value[T]) DefaultValue(r *http.Request) any {
+	return v.fallback(r)
+}
+
+// fallback returns the configured default or zero value of type
+func (v *Value[T]) fallback(r *http.Request) T {
+	if v.defaultFunc != nil {
+		return v.defaultFunc(r)
+	}
+	return v.rDefault
+}
+
+func (v *Value[T]) Required() bool {
+	return v.required
+}
+
+// As sets alias name for field
+func (v *Value[T]) As(n string) *Value[T] {
+	v.alias = n
+	return v
+}
+
+// Optional marks value resolver as optional value
+func (v *Value[T]) Optional() *Value[T] {
+	v.required = false
+	return v
+}
+
+// Default sets fallback value and marks value resolver as optional
+func (v *Value[T]) Default(val T) *Value[T] {
+	return v.DefaultFunc(func(*http.Request) T {
+		return val
+	})
+}
+
+// DefaultFunc sets fallback value resolver and marks value resolver as optional
+func (v *Value[T]) DefaultFunc(cb func(*http.Request) T) *Value[T] {
+	v.defaultFunc = cb
+	v.required = false
+	return v
+}
+
+// Transform adds transformation step applied to
+// resolved value before running validations
+func (v *Value[T]) Transform(cb func(T) (T, error)) *Value[T] {
+	v.transforms = append(v.transforms, cb)
+	return v
+}
+
+// Validate adds value validator to resolved value
+func (v *Value[T]) Validate(cb func(*http.Request, T) error) *Value[T] {
+	v.validate = cb
+	return v
+}
+
+// Rule adds validation [Rule] to resolved value
+func (v *Value[T]) Rule(rules ...Rule) *Value[T] {
+	v.rules = append(v.rules, rules...)
+	return v
+}
+
+// Min validates minimum number or length of string and lists
+func (v *Value[T]) Min(n float64) *Value[T] {
+	return v.Rule(RuleMin(n))
+}
+
+// Max validates maximum number or length of string and lists
+func (v *Value[T]) Max(n float64) *Value[T] {
+	return v.Rule(RuleMax(n))
+}
+
+// Pattern validates value matches the regular expression
+// and panics on invalid expression
+func (v *Value[T]) Pattern(expr string) *Value[T] {
+	return v.Rule(RulePattern(regexp.MustCompile(expr)))
+}
+
+// OneOf validates value is one of the options
+func (v *Value[T]) OneOf(options ...T) *Value[T] {
+	return v.Rule(func(rv reflect.Value) string {
+		for _, opt := range options {
+			if reflect.DeepEqual(rv.Interface(), opt) {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %v", options)
+	})
+}
+
+// check runs validation rules of value and struct fields
+func (v *Value[T]) check(value T) error {
+	errs := make(JSON)
+	rv := reflect.ValueOf(&value).Elem()
+
+	if msg := checkRules(v.rules, rv); len(msg) > 0 {
+		errs[v.Alias()] = msg
+	}
+	validateStruct(rv, v.Alias(), errs)
+
+	if len(errs) > 0 {
+		return ValidationError(errs)
+	}
+	return nil
+}
+
+// Codec decodes cookie values using [CookieCodec]
+func (v *Value[T]) Codec(c CookieCodec) *Value[T] {
+	v.codec = c
+	return v
+}
+
+// Split splits values by separator when resolving slice type
+// i.e. Split(",") reads ?ids=1,2,3 as []int{1, 2, 3}
+func (v *Value[T]) Split(sep string) *Value[T] {
+	v.separator = sep
+	return v
+}
+
+// Strict rejects unknown fields when decoding json body into struct
+func (v *Value[T]) Strict() *Value[T] {
+	v.strict = true
+	return v
+}
+
+// Reader stores custom value reader for value resolver
+func (v *Value[T]) Reader(cb func(http.ResponseWriter, *http.Request) (T, error)) *Value[T] {
+	v.reader = cb
+	return v
+}
+
+// readFileValue reads uploaded file(s) from *[http.Request]
+func (v *Value[T]) readFileValue(r *http.Request) (T, error) {
+	files := FormFiles(r, v.field)
+	if len(files) > 0 {
+		if val, ok := any(files).(T); ok {
+			return val, nil
+		} else if val, ok := any(files[0]).(T); ok {
+			return val, nil
+		}
+	}
+
+	return v.rDefault, Errorf("resolve file failed [%s]", v.field).Reason("value_failed")
+}
+
+// readBoundStruct reads the struct using [bindingPlan] where tagged
+// fields are read from their own source and rest from provided source
+func (v *Value[T]) readBoundStruct(r *http.Request, src ValueSource, plan *bindingPlan) (T, error) {
+	var data T
+	rv := reflect.ValueOf(&data).Elem()
+	if rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		rv = rv.Elem()
+	}
+
+	found := false
+	bodyKeys := make(map[string]bool)
+	for _, fb := range plan.fields {
+		sources := []ValueSource{src}
+		if fb.tagged {
+			sources = []ValueSource{fb.source}
+		}
+		if sources[0] == ValueSourceDefault {
+			sources = defaultSources(r)
+		}
+
+		for _, fsrc := range sources {
+			if fsrc == ValueSourceBody {
+				bodyKeys[bodyKey(fb.name)] = true
+			}
+
+			raw, ok, err := lookupSource(r, fsrc, fb.name, isMultiValue(fb.rType), v.codec)
+			if err != nil {
+				return v.rDefault, err
+			} else if !ok {
+				continue
+			}
+
+			val, err := convertValue(fb.rType, raw)
+			if err != nil {
+				return v.rDefault, err
+			}
+
+			rv.FieldByIndex(fb.index).Set(val)
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return v.rDefault, Errorf("resolve value failed [%s]", v.rType).Reason("value_failed")
+	}
+
+	if v.strict {
+		err := strictBodyKeys(r, bodyKeys)
+		if err != nil {
+			return v.rDefault, err
+		}
+	}
+	return data, nil
+}
+
+// bodyKey returns top level body key of dotted path or JSON Pointer
+func bodyKey(path string) string {
+	path = strings.TrimPrefix(path, "/")
+	key, _, _ := strings.Cut(path, "/")
+	if key == path {
+		key, _, _ = strings.Cut(path, ".")
+	}
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~1", "/"), "~0", "~")
+}
+
+// strictBodyKeys rejects body keys not bound to any struct field
+func strictBodyKeys(r *http.Request, known map[string]bool) error {
+	keys := make([]string, 0)
+	for key := range BodyJSON(r) {
+		keys = append(keys, key)
+	}
+	for key := range r.PostForm {
+		keys = append(keys, key)
+	}
+
+	for _, key := range keys {
+		if !known[key] {
+			return Errorf("invalid body: unknown field %q", key).
+				Status(http.StatusBadRequest).
+				Reason("body_invalid")
+		}
+	}
+	return nil
+}
+
+// readValue reads the value from *[http.Request] for provided [ValueSource]
+func (v *Value[T]) readValue(r *http.Request, src ValueSource) (T, error) {
+	if src == ValueSourceFile {
+		return v.readFileValue(r)
+	}
+
+	asStruct := !isMultiValue(v.rType) && (v.rType.Kind() == reflect.Struct ||
+		(v.rType.Kind() == reflect.Ptr && v.rType.Elem().Kind() == reflect.Struct)) &&
+		!isScalar(v.rType)
+
+	if asStruct && src == ValueSourceContext && len(v.field) > 0 {
+		return GetData(r, v.field, v.rDefault)
+	} else if asStruct {
+		plan := bindingPlanFor(v.rType)
+		if plan.tagged || src != ValueSourceBody {
+			return v.readBoundStruct(r, src, plan)
+		}
+
+		var data T
+
+		if len(v.field) > 0 {
+			bValue, ok := BodyRead(r, v.field)
+			if !ok {
+				return v.rDefault, Errorf("resolve value failed [%s]", v.field).Reason("value_failed")
+			}
+			return ReadValue(v.rType, bValue, v.rDefault)
+		}
+
+		ok, err := decodeBodyJSON(r, &data, v.strict)
+		if ok {
+			if err != nil {
+				return v.rDefault, err
+			}
+			return data, nil
+		}
+
+		err = BodyReadStruct(r, &data)
+		if err != nil {
+			return v.rDefault, err
+		}
+		return data, nil
+	} else if src == ValueSourceContext && !isMultiValue(v.rType) {
+		return GetData(r, v.field, v.rDefault)
+	} else if src == ValueSourceBody && len(v.field) < 1 {
+		bData := BodyData(r)
+		if bData == nil && BodyRaw(r) != nil {
+			bData = string(BodyRaw(r))
+		}
+		if bData != nil {
+			return ReadValue(v.rType, bData, v.rDefault)
+		}
+	} else {
+		multi := isMultiValue(v.rType)
+		raw, ok, err := lookupSource(r, src, v.field, multi, v.codec)
+		if err != nil {
+			return v.rDefault, err
+		} else if ok {
+			if multi {
+				return readSlice(v.rType, raw.([]any), v.separator, v.rDefault)
+			}
+			return ReadValue(v.rType, raw, v.rDefault)
+		}
+	}
+
+	return v.rDefault, Errorf("resolve value failed [%s]", v.field).Reason("value_failed")
+}
+
+// defaultSources lists the sources to read from based on request method
+func defaultSources(r *http.Request) []ValueSource {
+	if HasBody(r) {
+		return []ValueSource{ValueSourceBody}
+	}
+	return []ValueSource{ValueSourceGet, ValueSourcePath}
+}
+
+// readDefaultValue reads the value from *[http.Request] based on request method
+func (v Value[T]) readDefaultValue(r *http.Request) (T, error) {
+	var value T
+	var err error
+
+	for _, t := range defaultSources(r) {
+		value, err = v.readValue(r, t)
+		if err == nil {
+			return value, nil
+		}
+	}
+
+	return value, err
+}
+
+func (v Value[T]) Resolve(w http.ResponseWriter, r *http.Request) (any, error) {
+	if v.reader != nil {
+		return v.reader(w, r)
+	}
+
+	var value T
+	var err error
+	if v.source == ValueSourceDefault {
+		value, err = v.readDefaultValue(r)
+	} else {
+		value, err = v.readValue(r, v.source)
+	}
+
+	if err != nil {
+		if v.required {
+			return v.rDefault, err
+		}
+
+		return v.fallback(r), nil
+	}
+
+	for _, transform := range v.transforms {
+		value, err = transform(value)
+		if err != nil {
+			return v.rDefault, err
+		}
+	}
+
+	err = v.check(value)
+	if err != nil {
+		return v.rDefault, err
+	}
+
+	if v.validate != nil {
+		err := v.validate(r, value)
+		if err != nil {
+			return v.rDefault, err
+		}
+	}
+
+	return value, nil
+}
+
+// NewValue define new value resolver instance
+func NewValue[T any](field string, src ValueSource) *Value[T] {
+	tp := reflect.TypeOf((*T)(nil)).Elem()
+
+	var def T
+	if tp.Kind() != reflect.Interface {
+		def = (reflect.New(tp).Elem().Interface()).(T)
+	}
+
+	// prepare struct plans to panic early on invalid tags
+	bindingPlanFor(tp)
+
+	return &Value[T]{
+		field:    field,
+		rType:    tp,
+		rDefault: def,
+		source:   src,
+		required: true,
+	}
+}
+
+// StructFrom defines new struct resolver instance
+func StructFrom[T any](src ValueSource) *Value[T] {
+	return NewValue[T]("", src)
+}
+
+// Struct defines struct resolver for default source
+func Struct[T any]() *Value[T] {
+	return StructFrom[T](ValueSourceDefault)
+}
+
+// Get defines value resolver from query string
+func Get[T any](field string) *Value[T] {
+	return NewValue[T](field, ValueSourceGet)
+}
+
+// Body defines value resolver from request body
+func Body[T any](field string) *Value[T] {
+	return NewValue[T](field, ValueSourceBody)
+}
+
+// Path defines value resolver from url path params
+func Path[T any](field string) *Value[T] {
+	return NewValue[T](field, ValueSourcePath)
+}
+
+// Header defines value resolver from request header
+func Header[T any](field string) *Value[T] {
+	return NewValue[T](field, ValueSourceHeader)
+}
+
+// Context defines value resolver from default data on request context
+func Context[T any](key string) *Value[T] {
+	return NewValue[T](key, ValueSourceContext)
+}
+
+// Reader defines value resolver using custom reader
+func Reader[T any](cb func(http.ResponseWriter, *http.Request) (T, error)) *Value[T] {
+	return NewValue[T]("", ValueSourceDefault).Reader(cb)
+}
+
+// ClientIP defines value resolver of client ip from request remote address
+func ClientIP() *Value[string] {
+	return Reader(func(_ http.ResponseWriter, r *http.Request) (string, error) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return r.RemoteAddr, nil
+		}
+		return host, nil
+	}).As("client_ip")
+}
@@ -1,53 +1,267 @@
-package hndlor_test
-
-import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"net/http"
-	"testing"
-
-	"github.com/OpenRunic/hndlor"
-)
-
-func TestValueResolve(t *testing.T) {
-	body := hndlor.JSON{
-		"username": "admin",
-		"password": "pass",
-	}
-	bodyBytes, _ := json.Marshal(body)
-
-	req, err := http.NewRequest("POST", "/?name=John", bytes.NewBuffer(bodyBytes))
-
-	if err != nil {
-		t.Error(err)
-	} else {
-		req.SetPathValue("uid", "100")
-		req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
-		req.Header.Set("x-api-token", "xyz")
-		req, _ = hndlor.PrepareBody(hndlor.PatchValue(req, "identifier", "sample-iden"))
-
-		_, err := hndlor.Values(nil, req,
-			hndlor.Get[string]("name"),
-			hndlor.Get[string]("q").Optional(),
-			hndlor.Body[string]("username"),
-			hndlor.Path[string]("uid"),
-			hndlor.Header[string]("X-Api-Token").As("token"),
-			hndlor.Context[string]("identifier"),
-			hndlor.Reader(func(_ http.ResponseWriter, _ *http.Request) (int, error) {
-				return 10, nil
-			}).As("rank"),
-			hndlor.Struct[TestLoginCredentials]().As("login").Validate(func(_ *http.Request, tlc TestLoginCredentials) error {
-				if len(tlc.Username) > 0 {
-					return nil
-				}
-				return errors.New("unable to resolve login data")
-			}),
-			hndlor.HTTPRequest().As("req"),
-			hndlor.HTTPContext().As("context"),
-		)
-		if err != nil {
-			t.Error(err)
-		}
-	}
-}
+package hndlor_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/OpenRunic/hndlor"
+)
+
+func TestValueResolve(t *testing.T) {
+	body := hndlor.JSON{
+		"username": "admin",
+		"password": "pass",
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req, err := http.NewRequest("POST", "/?name=John", bytes.NewBuffer(bodyBytes))
+
+	if err != nil {
+		t.Error(err)
+	} else {
+		req.SetPathValue("uid", "100")
+		req.Header.Set("Content-Type", hndlor.ContentTypeJSON)
+		req.Header.Set("x-api-token", "xyz")
+		req, _ = hndlor.PrepareBody(hndlor.PatchValue(req, "identifier", "sample-iden"))
+
+		_, err := hndlor.Values(nil, req,
+			hndlor.Get[string]("name"),
+			hndlor.Get[string]("q").Optional(),
+			hndlor.Body[string]("username"),
+			hndlor.Path[string]("uid"),
+			hndlor.Header[string]("X-Api-Token").As("token"),
+			hndlor.Context[string]("identifier"),
+			hndlor.Reader(func(_ http.ResponseWriter, _ *http.Request) (int, error) {
+				return 10, nil
+			}).As("rank"),
+			hndlor.Struct[TestLoginCredentials]().As("login").Validate(func(_ *http.Request, tlc TestLoginCredentials) error {
+				if len(tlc.Username) > 0 {
+					return nil
+				}
+				return errors.New("unable to resolve login data")
+			}),
+			hndlor.HTTPRequest().As("req"),
+			hndlor.HTTPContext().As("context"),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestSliceValueResolve(t *testing.T) {
+	req, err := http.NewRequest("GET", "/?id=1&id=2&tag[]=a&tag[]=b&rank[1]=20&rank[0]=10&csv=x,y,,z", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Add("X-Scope", "read")
+	req.Header.Add("X-Scope", "write")
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Get[[]int]("id"),
+		hndlor.Get[[]string]("tag"),
+		hndlor.Get[[]int32]("rank"),
+		hndlor.Get[[]string]("csv").Split(","),
+		hndlor.Header[[]string]("X-Scope").As("scopes"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := hndlor.JSON{
+		"id":     []int{1, 2},
+		"tag":    []string{"a", "b"},
+		"rank":   []int32{10, 20},
+		"csv":    []string{"x", "y", "z"},
+		"scopes": []string{"read", "write"},
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("unable to resolve slice values: %v", values)
+	}
+}
+
+type TestMoney struct {
+	Cents int64
+}
+
+func TestReadValueTypes(t *testing.T) {
+	hndlor.RegisterConverter(func(s string) (TestMoney, error) {
+		f, err := strconv.ParseFloat(s, 64)
+		return TestMoney{Cents: int64(f * 100)}, err
+	})
+
+	req, err := http.NewRequest("GET", "/?i=1&i8=-8&i32=32&u=7&u16=16&f32=1.5&b=true&t=2024-01-02T03:04:05Z&d=1m30s&ip=10.0.0.1&amount=12.34&p=5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Get[int]("i"),
+		hndlor.Get[int8]("i8"),
+		hndlor.Get[int32]("i32"),
+		hndlor.Get[uint]("u"),
+		hndlor.Get[uint16]("u16"),
+		hndlor.Get[float32]("f32"),
+		hndlor.Get[bool]("b"),
+		hndlor.Get[time.Time]("t"),
+		hndlor.Get[time.Duration]("d"),
+		hndlor.Get[netip.Addr]("ip"),
+		hndlor.Get[TestMoney]("amount"),
+		hndlor.Get[*int]("p"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if values["i"] != 1 || values["i8"] != int8(-8) || values["i32"] != int32(32) ||
+		values["u"] != uint(7) || values["u16"] != uint16(16) || values["f32"] != float32(1.5) ||
+		values["b"] != true || values["d"] != 90*time.Second ||
+		values["ip"] != netip.MustParseAddr("10.0.0.1") ||
+		values["amount"] != (TestMoney{Cents: 1234}) || *values["p"].(*int) != 5 {
+		t.Errorf("unable to resolve typed values: %v", values)
+	}
+
+	if !values["t"].(time.Time).Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Error("unable to resolve time value")
+	}
+
+	_, err = hndlor.Get[uint8]("i8").Resolve(nil, req)
+	if err == nil {
+		t.Error("unable to detect out of range value")
+	}
+}
+
+func TestValueDefaultsAndTransforms(t *testing.T) {
+	req, err := http.NewRequest("GET", "/?q=+Hello+World+&limit=500", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Get[string]("q").Transform(hndlor.Trim).Transform(hndlor.Lowercase),
+		hndlor.Get[int]("page").Default(1),
+		hndlor.Get[int]("limit").Default(20).Transform(hndlor.Clamp(1, 100)),
+		hndlor.Get[string]("sort").DefaultFunc(func(r *http.Request) string {
+			return "created_at"
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := hndlor.JSON{
+		"q":     "hello world",
+		"page":  1,
+		"limit": 100,
+		"sort":  "created_at",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("unable to resolve default and transformed values: %v", values)
+	}
+}
+
+func TestContextKeys(t *testing.T) {
+	userKey := hndlor.NewKey[TestPrincipal]("user")
+
+	parent, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent = hndlor.PatchValue(parent, "tenant", "main")
+
+	child := hndlor.SetKey(parent, userKey, TestPrincipal{Username: "admin"})
+	child = hndlor.PatchValue(child, "tenant", "other")
+
+	if tenant, _ := hndlor.GetData(parent, "tenant", ""); tenant != "main" {
+		t.Errorf("child request leaked data into parent: %s", tenant)
+	}
+	if _, err := hndlor.GetKey(parent, userKey); err == nil {
+		t.Error("expected parent request to not have user key")
+	}
+
+	user, err := hndlor.GetKey(child, userKey)
+	if err != nil || user.Username != "admin" {
+		t.Errorf("unable to read typed key: %v, %v", user, err)
+	}
+
+	values, err := hndlor.Values(nil, child, userKey.Resolver())
+	if err != nil || values["user"].(TestPrincipal).Username != "admin" {
+		t.Errorf("unable to resolve typed key: %v, %v", values, err)
+	}
+
+	_, err = hndlor.GetData(child, "tenant", 0)
+	if rErr, ok := err.(*hndlor.ResponseError); !ok || rErr.ResponseJSON()["reason"] != "context_type_mismatch" {
+		t.Errorf("expected type mismatch error: %v", err)
+	}
+}
+
+type TestPrincipal struct {
+	Username string
+}
+
+type TestContextTags struct {
+	IDs  []int    `hndlor:"context=tags"`
+	Tags []string `hndlor:"context=tags"`
+}
+
+func TestContextSliceValues(t *testing.T) {
+	req, err := http.NewRequest("GET", "/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = hndlor.PatchValue(req, "tags", []string{"1", "2"})
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.Context[[]string]("tags"),
+		hndlor.Context[[]int]("tags").As("ids"),
+		hndlor.Context[TestContextTags]("").As("bound"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bound := values["bound"].(TestContextTags)
+	if !reflect.DeepEqual(values["tags"], []string{"1", "2"}) ||
+		!reflect.DeepEqual(values["ids"], []int{1, 2}) ||
+		!reflect.DeepEqual(bound.IDs, []int{1, 2}) || len(bound.Tags) != 2 {
+		t.Errorf("unable to resolve context slice values: %v", values)
+	}
+}
+
+type TestPageQuery struct {
+	Page  int    `validate:"min=1"`
+	Limit int    `json:"per_page"`
+	Sort  string `json:"sort,omitempty"`
+}
+
+func TestUntaggedStructSources(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?page=2&per_page=25&sort=name", nil)
+	req.Header.Set("Page", "3")
+
+	values, err := hndlor.Values(nil, req,
+		hndlor.StructFrom[TestPageQuery](hndlor.ValueSourceGet).As("query"),
+		hndlor.StructFrom[*TestPageQuery](hndlor.ValueSourceHeader).As("header"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := values["query"].(TestPageQuery)
+	header := values["header"].(*TestPageQuery)
+	if query.Page != 2 || query.Limit != 25 || query.Sort != "name" || header.Page != 3 {
+		t.Errorf("unable to bind untagged struct: %v", values)
+	}
+
+	req, _ = http.NewRequest("GET", "/?page=0", nil)
+	_, err = hndlor.Values(nil, req, hndlor.StructFrom[TestPageQuery](hndlor.ValueSourceGet).As("query"))
+	if re, ok := err.(*hndlor.ResponseError); !ok || re.ResponseStatus() != 422 {
+		t.Errorf("expected validate tags to run on untagged struct: %v", err)
+	}
+}
@@ -1,57 +1,137 @@
-package hndlor
-
-import (
-	"context"
-	"net/http"
-)
-
-// Values collects all provided values from *[http.Request]
-func Values(w http.ResponseWriter, r *http.Request, values ...ValueResolver) (JSON, error) {
-	res := make(JSON)
-
-	for _, val := range values {
-		if len(val.Alias()) > 0 {
-			v, err := val.Resolve(w, r)
-			if err != nil {
-				return nil, err
-			}
-			res[val.Alias()] = v
-		}
-	}
-
-	return res, nil
-}
-
-// ValuesAs collects values and maps it to struct
-func ValuesAs(w http.ResponseWriter, r *http.Request, data any, values ...ValueResolver) error {
-	vs, err := Values(w, r, values...)
-	if err != nil {
-		return err
-	}
-
-	return StructToStruct(vs, data)
-}
-
-// HTTPRequest defines value resolver to access *[http.Request]
-func HTTPRequest() *Value[*http.Request] {
-	return NewValue[*http.Request]("", ValueSourceDefault).
-		Reader(func(_ http.ResponseWriter, r *http.Request) (*http.Request, error) {
-			return r, nil
-		})
-}
-
-// HTTPResponseWriter defines value resolver to access [http.ResponseWriter]
-func HTTPResponseWriter() *Value[http.ResponseWriter] {
-	return NewValue[http.ResponseWriter]("", ValueSourceDefault).
-		Reader(func(w http.ResponseWriter, _ *http.Request) (http.ResponseWriter, error) {
-			return w, nil
-		})
-}
-
-// HTTPContext defines value resolver to access request's [context.Context]
-func HTTPContext() *Value[context.Context] {
-	return NewValue[context.Context]("", ValueSourceDefault).
-		Reader(func(_ http.ResponseWriter, r *http.Request) (context.Context, error) {
-			return r.Context(), nil
-		})
-}
+package hndlor
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"net/http"
+	"strconv"
+)
+
+// Values collects all provided values from *[http.Request]
+func Values(w http.ResponseWriter, r *http.Request, values ...ValueResolver) (JSON, error) {
+	res := make(JSON)
+
+	for _, val := range values {
+		if len(val.Alias()) > 0 {
+			v, err := val.Resolve(w, r)
+			if err != nil {
+				return nil, err
+			}
+			res[val.Alias()] = v
+		}
+	}
+
+	return res, nil
+}
+
+// ValuesAll collects all provided values from *[http.Request] and
+// reports every failure at once as [ValidationError] keyed by alias
+func ValuesAll(w http.ResponseWriter, r *http.Request, values ...ValueResolver) (JSON, error) {
+	resolved, err := resolveAll(w, r, values, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(JSON)
+	for i, val := range values {
+		if len(val.Alias()) > 0 {
+			res[val.Alias()] = resolved[i]
+		}
+	}
+
+	return res, nil
+}
+
+// resolveAll resolves every value while collecting the failures
+// allowed by optional [ValueFailHandler] into single [ValidationError]
+func resolveAll(w http.ResponseWriter, r *http.Request, values []ValueResolver, onFail ValueFailHandler) ([]any, error) {
+	errs := make(JSON)
+	resolved := make([]any, len(values))
+
+	for i, value := range values {
+		val, err := value.Resolve(w, r)
+
+		if err != nil {
+			if onFail != nil {
+				err = onFail(value, err)
+			}
+
+			if err != nil && isServerError(err) {
+				return nil, err
+			} else if err != nil {
+				collectError(errs, value, i, err)
+			}
+			resolved[i] = value.DefaultValue(r)
+		} else {
+			resolved[i] = val
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, ValidationError(errs)
+	}
+	return resolved, nil
+}
+
+// isServerError checks if error is server side and must not be aggregated
+func isServerError(err error) bool {
+	var rErr *ResponseError
+	return errors.As(err, &rErr) &&
+		(rErr.serverError || rErr.statusCode >= http.StatusInternalServerError)
+}
+
+// collectError adds the resolve error of value to the errors map
+// merging field errors of nested [ValidationError]
+func collectError(errs JSON, value ValueResolver, index int, err error) {
+	key := value.Alias()
+	if len(key) < 1 {
+		key = strconv.Itoa(index)
+	}
+
+	var rErr *ResponseError
+	if !errors.As(err, &rErr) {
+		errs[key] = err.Error()
+		return
+	}
+
+	if fields, ok := rErr.extras["errors"].(JSON); ok {
+		maps.Copy(errs, fields)
+	} else {
+		errs[key] = rErr.Message()
+	}
+}
+
+// ValuesAs collects values and maps it to struct
+func ValuesAs(w http.ResponseWriter, r *http.Request, data any, values ...ValueResolver) error {
+	vs, err := Values(w, r, values...)
+	if err != nil {
+		return err
+	}
+
+	return StructToStruct(vs, data)
+}
+
+// HTTPRequest defines value resolver to access *[http.Request]
+func HTTPRequest() *Value[*http.Request] {
+	return NewValue[*http.Request]("", ValueSourceDefault).
+		Reader(func(_ http.ResponseWriter, r *http.Request) (*http.Request, error) {
+			return r, nil
+		})
+}
+
+// HTTPResponseWriter defines value resolver to access [http.ResponseWriter]
+func HTTPResponseWriter() *Value[http.ResponseWriter] {
+	return NewValue[http.ResponseWriter]("", ValueSourceDefault).
+		Reader(func(w http.ResponseWriter, _ *http.Request) (http.ResponseWriter, error) {
+			return w, nil
+		})
+}
+
+// HTTPContext defines value resolver to access request's [context.Context]
+func HTTPContext() *Value[context.Context] {
+	return NewValue[context.Context]("", ValueSourceDefault).
+		Reader(func(_ http.ResponseWriter, r *http.Request) (context.Context, error) {
+			return r.Context(), nil
+		})
+}
@@ -1,83 +1,132 @@
-package hndlor
-
-import (
-	"encoding/json"
-	"fmt"
-	"io"
-	"log"
-	"net/http"
-)
-
-// JSON represents json data format
-type JSON map[string]any
-
-// AsLoggable defines interface to check if struct is loggable
-type AsLoggable interface {
-	Log(io.Writer)
-}
-
-// WriteData writes [JSON] to [io.Writer]
-func WriteData(w io.Writer, data JSON) error {
-	bt, e := json.Marshal(data)
-	if e != nil {
-		return e
-	}
-
-	rs, ok := w.(http.ResponseWriter)
-	if ok {
-		rs.Header().Add("Content-Type", "application/json")
-	}
-
-	_, e = w.Write(bt)
-	return e
-}
-
-// WriteError writes [error] to [io.Writer] and tries
-// to use [AsExportableResponse] when available
-func WriteError(w io.Writer, err error) error {
-	var data JSON
-	statusCode := 0
-	ex, ok := err.(AsExportableResponse)
-	if ok {
-		data = ex.ResponseJSON()
-		statusCode = ex.ResponseStatus()
-	} else {
-		data = JSON{
-			"error": err.Error(),
-		}
-	}
-
-	if statusCode > 0 {
-		rw, ok := w.(http.ResponseWriter)
-		if ok {
-			rw.WriteHeader(statusCode)
-		}
-	}
-
-	LogError(log.Writer(), err)
-	return WriteData(w, data)
-}
-
-// WriteMessage writes message to [io.Writer]
-func WriteMessage(w io.Writer, msg string) error {
-	return WriteData(w, JSON{
-		"message": msg,
-	})
-}
-
-// WriteError writes error message to [io.Writer]
-func WriteErrorMessage(w io.Writer, err string) error {
-	return WriteData(w, JSON{
-		"error": err,
-	})
-}
-
-// LogError prints log to [io.Writer]
-func LogError(w io.Writer, err error) {
-	le, ok := err.(AsLoggable)
-	if ok {
-		le.Log(w)
-	} else {
-		fmt.Fprintf(w, "[ERR] %s\n", err.Error())
-	}
-}
+package hndlor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// JSON represents json data format
+type JSON map[string]any
+
+// AsLoggable defines interface to check if struct is loggable
+type AsLoggable interface {
+	Log(io.Writer)
+}
+
+// WriteData writes data to [io.Writer] using [Encoder]
+// negotiated from optional *[http.Request] else as json
+func WriteData(w io.Writer, data any, rs ...*http.Request) error {
+	return writeData(w, 0, data, firstRequest(rs))
+}
+
+// writeData encodes data and writes with status code to [io.Writer]
+func writeData(w io.Writer, statusCode int, data any, r *http.Request) error {
+	mediaType, enc, err := NegotiateEncoder(r)
+	if err != nil {
+		return err
+	}
+
+	return writeEncoded(w, statusCode, data, mediaType, enc)
+}
+
+// writeEncoded encodes data via [Encoder] and writes with status code to [io.Writer]
+func writeEncoded(w io.Writer, statusCode int, data any, mediaType string, enc Encoder) error {
+	var buf bytes.Buffer
+	e := enc(&buf, data)
+	if e != nil {
+		return e
+	}
+
+	rs, ok := w.(http.ResponseWriter)
+	if ok {
+		if strings.HasPrefix(mediaType, "text/") {
+			mediaType += "; charset=utf-8"
+		}
+		rs.Header().Set("Content-Type", mediaType)
+		if statusCode > 0 {
+			rs.WriteHeader(statusCode)
+		}
+	}
+
+	_, e = w.Write(buf.Bytes())
+	return e
+}
+
+// WriteError writes [error] to [io.Writer] and tries
+// to use [AsExportableResponse] when available
+//
+// Error is written as json when negotiation
+// with optional *[http.Request] fails
+func WriteError(w io.Writer, err error, rs ...*http.Request) error {
+	r := firstRequest(rs)
+	if rErr, ok := err.(*ResponseError); ok && r != nil && len(rErr.requestID) < 1 {
+		// copy keeps shared errors untouched while logging request id
+		if id := GetRequestID(r); len(id) > 0 {
+			traced := *rErr
+			err = traced.RequestID(id)
+		}
+	}
+
+	var data JSON
+	statusCode := 0
+	ex, ok := err.(AsExportableResponse)
+	if ok {
+		data = ex.ResponseJSON()
+		statusCode = ex.ResponseStatus()
+	} else {
+		data = JSON{
+			"error": err.Error(),
+		}
+	}
+
+	if r != nil {
+		id := GetRequestID(r)
+		if _, ok := data["request_id"]; !ok && len(id) > 0 {
+			data["request_id"] = id
+		}
+	}
+
+	LogError(log.Writer(), err)
+
+	mediaType, enc, nerr := NegotiateEncoder(r)
+	if nerr != nil {
+		mediaType, enc = ContentTypeJSON, EncodeJSON
+	}
+	return writeEncoded(w, statusCode, data, mediaType, enc)
+}
+
+// WriteMessage writes message to [io.Writer]
+func WriteMessage(w io.Writer, msg string, rs ...*http.Request) error {
+	return WriteData(w, JSON{
+		"message": msg,
+	}, rs...)
+}
+
+// WriteError writes error message to [io.Writer]
+func WriteErrorMessage(w io.Writer, err string, rs ...*http.Request) error {
+	return WriteData(w, JSON{
+		"error": err,
+	}, rs...)
+}
+
+// firstRequest returns the optional *[http.Request]
+func firstRequest(rs []*http.Request) *http.Request {
+	if len(rs) > 0 {
+		return rs[0]
+	}
+	return nil
+}
+
+// LogError prints log to [io.Writer]
+func LogError(w io.Writer, err error) {
+	le, ok := err.(AsLoggable)
+	if ok {
+		le.Log(w)
+	} else {
+		fmt.Fprintf(w, "[ERR] %s\n", err.Error())
+	}
+}